@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type AbilityResponse struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	EffectEntries []struct {
+		Effect      string        `json:"effect"`
+		ShortEffect string        `json:"short_effect"`
+		Language    namedResource `json:"language"`
+	} `json:"effect_entries"`
+	Pokemon []struct {
+		IsHidden bool          `json:"is_hidden"`
+		Slot     int           `json:"slot"`
+		Pokemon  namedResource `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+// hiddenAbilityOdds is the 1 in N chance of a wild pokemon having its hidden
+// ability, close to the rate of hidden ability encounters in the later games.
+const hiddenAbilityOdds = 20
+
+func (c *apiClient) getAbility(name string) (AbilityResponse, error) {
+	const baseUrl = "https://pokeapi.co/api/v2/ability/"
+
+	var ability AbilityResponse
+	jsonData, err := c.getJsonFromCacheOrServer(baseUrl + name + "/")
+	if err != nil {
+		return ability, err
+	}
+
+	err = json.Unmarshal(jsonData, &ability)
+	if err != nil {
+		return ability, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+
+	return ability, nil
+}
+
+func (a AbilityResponse) effectText() string {
+	for _, entry := range a.EffectEntries {
+		if entry.Language.Name == "en" {
+			return strings.Join(strings.Fields(entry.Effect), " ")
+		}
+	}
+	return "No effect description"
+}
+
+// rollAbility picks one of the species' regular abilities, or rarely its
+// hidden ability. intn rolls a number below n, normally rand.Intn.
+func rollAbility(pokemon Pokemon, intn func(n int) int) string {
+	regular := []string{}
+	hidden := []string{}
+	for _, ability := range pokemon.Abilities {
+		if ability.IsHidden {
+			hidden = append(hidden, ability.Ability.Name)
+		} else {
+			regular = append(regular, ability.Ability.Name)
+		}
+	}
+
+	if len(hidden) > 0 && (len(regular) == 0 || intn(hiddenAbilityOdds) == 0) {
+		return hidden[intn(len(hidden))]
+	}
+	if len(regular) == 0 {
+		return ""
+	}
+	return regular[intn(len(regular))]
+}
+
+// speciesAbilities lists every ability a species can have, marking hidden ones.
+func speciesAbilities(pokemon Pokemon) string {
+	names := []string{}
+	for _, ability := range pokemon.Abilities {
+		name := ability.Ability.Name
+		if ability.IsHidden {
+			name += " (hidden)"
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+func commandAbility(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Must pass an ability to the ability command")
+	}
+
+	ability, err := conf.api.getAbility(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(conf.out, "Ability: %s\n", ability.Name)
+	fmt.Fprintf(conf.out, "Effect: %s\n", ability.effectText())
+	fmt.Fprintf(conf.out, "Pokemon with %s:\n", ability.Name)
+	for _, holder := range ability.Pokemon {
+		if holder.IsHidden {
+			fmt.Fprintf(conf.out, "\t- %s (hidden)\n", holder.Pokemon.Name)
+		} else {
+			fmt.Fprintf(conf.out, "\t- %s\n", holder.Pokemon.Name)
+		}
+	}
+
+	return nil
+}
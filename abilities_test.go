@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// rolls returns an intn that gives back the rolls in order.
+func rolls(values ...int) func(int) int {
+	return func(n int) int {
+		value := values[0]
+		values = values[1:]
+		return value % n
+	}
+}
+
+func TestRollAbility(t *testing.T) {
+	cases := []struct {
+		abilities string
+		rolls     []int
+		expected  string
+	}{
+		// The first roll picks the hidden ability on 0, the second picks which
+		{`[{"ability": {"name": "static"}}, {"ability": {"name": "lightning-rod"}, "is_hidden": true}]`, []int{0, 0}, "lightning-rod"},
+		{`[{"ability": {"name": "static"}}, {"ability": {"name": "lightning-rod"}, "is_hidden": true}]`, []int{1, 0}, "static"},
+		{`[{"ability": {"name": "overgrow"}}, {"ability": {"name": "chlorophyll"}}]`, []int{1}, "chlorophyll"},
+		// Without regular abilities the hidden one is always picked
+		{`[{"ability": {"name": "levitate"}, "is_hidden": true}]`, []int{0}, "levitate"},
+		{`[]`, []int{}, ""},
+	}
+
+	for _, c := range cases {
+		var pokemon Pokemon
+		err := json.Unmarshal([]byte(`{"abilities": `+c.abilities+`}`), &pokemon)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		actual := rollAbility(pokemon, rolls(c.rolls...))
+		if actual != c.expected {
+			t.Errorf("%s with rolls %v: actual %q != expected %q", c.abilities, c.rolls, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// builtinAliases are short names for the most used commands.
+var builtinAliases = map[string]string{
+	"e": "explore",
+	"c": "catch",
+	"i": "inspect",
+	"q": "exit",
+}
+
+// validShortcutName checks a new alias or macro name. Commands can't be
+// replaced, but the built in aliases can.
+func validShortcutName(name string) error {
+	if strings.ContainsAny(name, "$;") {
+		return fmt.Errorf("%s can't be used as a name, it has $ or ;", name)
+	}
+	if commands.IsCommand(name) {
+		return fmt.Errorf("%s is already a command", name)
+	}
+	return nil
+}
+
+// knownCommand reports whether a line starting with name could run, so
+// aliases and macros with typos are caught when they're defined.
+func knownCommand(conf *config, name string) bool {
+	if _, ok := commands.Lookup(name); ok {
+		return true
+	}
+	_, isAlias := conf.settings.Aliases[name]
+	_, isMacro := conf.settings.Macros[name]
+	return isAlias || isMacro
+}
+
+func printShortcuts(conf *config, shortcuts map[string]string, kind string) {
+	names := []string{}
+	for name := range shortcuts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(conf.out, "\t- %s: %s%s\n", name, shortcuts[name], kind)
+	}
+}
+
+func commandAlias(conf *config, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(conf.out, "Aliases:")
+		printShortcuts(conf, commands.Aliases(), " (built in)")
+		printShortcuts(conf, conf.settings.Aliases, "")
+		return nil
+	}
+
+	if args[0] == "--delete" {
+		if len(args) != 2 {
+			return errors.New("Usage: alias --delete <name>")
+		}
+		name := strings.ToLower(args[1])
+		if _, ok := conf.settings.Aliases[name]; !ok {
+			return fmt.Errorf("You have no alias named %s", name)
+		}
+		delete(conf.settings.Aliases, name)
+		fmt.Fprintf(conf.out, "Removed the alias %s\n", name)
+		return nil
+	}
+
+	if len(args) < 2 {
+		return errors.New("Usage: alias <name> <command...>")
+	}
+	name := strings.ToLower(args[0])
+	err := validShortcutName(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := conf.settings.Macros[name]; ok {
+		return fmt.Errorf("%s is already a macro", name)
+	}
+	if !knownCommand(conf, strings.ToLower(args[1])) {
+		return fmt.Errorf("Unknown command %s", args[1])
+	}
+
+	if conf.settings.Aliases == nil {
+		conf.settings.Aliases = map[string]string{}
+	}
+	conf.settings.Aliases[name] = strings.Join(args[1:], " ")
+	fmt.Fprintf(conf.out, "%s now runs %s\n", name, conf.settings.Aliases[name])
+	return nil
+}
+
+func commandMacro(conf *config, args []string) error {
+	if len(args) == 0 {
+		if len(conf.settings.Macros) == 0 {
+			fmt.Fprintln(conf.out, "No macros yet, add one with macro <name> <command $1; command...>")
+			return nil
+		}
+		fmt.Fprintln(conf.out, "Macros:")
+		printShortcuts(conf, conf.settings.Macros, "")
+		return nil
+	}
+
+	if args[0] == "--delete" {
+		if len(args) != 2 {
+			return errors.New("Usage: macro --delete <name>")
+		}
+		name := strings.ToLower(args[1])
+		if _, ok := conf.settings.Macros[name]; !ok {
+			return fmt.Errorf("You have no macro named %s", name)
+		}
+		delete(conf.settings.Macros, name)
+		fmt.Fprintf(conf.out, "Removed the macro %s\n", name)
+		return nil
+	}
+
+	if len(args) < 2 {
+		return errors.New("Usage: macro <name> <command $1; command...>")
+	}
+	name := strings.ToLower(args[0])
+	err := validShortcutName(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := conf.settings.Aliases[name]; ok {
+		return fmt.Errorf("%s is already an alias", name)
+	}
+
+	body := strings.Join(args[1:], " ")
+	for _, command := range strings.Split(body, ";") {
+		words := strings.Fields(command)
+		if len(words) > 0 && !knownCommand(conf, strings.ToLower(words[0])) {
+			return fmt.Errorf("Unknown command %s", words[0])
+		}
+	}
+
+	if conf.settings.Macros == nil {
+		conf.settings.Macros = map[string]string{}
+	}
+	conf.settings.Macros[name] = body
+	fmt.Fprintf(conf.out, "%s now runs %s\n", name, body)
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAliasesAndMacros(t *testing.T) {
+	initCommands()
+	conf, out := newTestConfig(t, map[string]string{
+		"https://pokeapi.co/api/v2/location-area/viridian-forest-area/": viridianForestJson,
+		"https://pokeapi.co/api/v2/pokemon/caterpie/":                   caterpieJson,
+		"https://pokeapi.co/api/v2/pokemon/pikachu/":                    pikachuJson,
+	})
+	session := newSession(conf, strings.NewReader(""), out)
+
+	for _, line := range []string{"alias ex explore", "macro look ex $1; help", "e viridian-forest-area", "look viridian-forest-area"} {
+		err := session.Execute(line)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", line, err)
+		}
+	}
+	if strings.Count(out.String(), "- pikachu") != 2 {
+		t.Errorf("expected both explores to run, got %q", out.String())
+	}
+
+	for _, line := range []string{"alias explore map", "alias look help", "alias x nonsense", "macro m help; nonsense"} {
+		if err := session.Execute(line); err == nil {
+			t.Errorf("%s: expected an error", line)
+		}
+	}
+
+	save, err := readSave(conf.saveFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if save.Settings.Aliases["ex"] != "explore" || save.Settings.Macros["look"] != "ex $1; help" {
+		t.Errorf("shortcuts weren't saved: %+v", save.Settings)
+	}
+
+	err = session.Execute("alias --delete ex")
+	if err != nil || len(conf.settings.Aliases) != 0 {
+		t.Errorf("expected ex to be removed, got %v and %v", err, conf.settings.Aliases)
+	}
+}
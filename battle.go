@@ -0,0 +1,500 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mikeheiberger/pokedexcli/internal/battle"
+)
+
+// activeBattle is the battle in progress along with the caught pokemon that
+// make up the player's team and the species of the opponent's team, in the
+// same order as the battle teams.
+type activeBattle struct {
+	battle    *battle.Battle
+	team      []*CaughtPokemon
+	opponents []Pokemon
+
+	// rewarded counts the defeats that experience has been given out for
+	rewarded int
+}
+
+// battleCommands are the only commands that can be used during a battle.
+var battleCommands = map[string]bool{
+	"help":    true,
+	"exit":    true,
+	"battle":  true,
+	"fight":   true,
+	"switch":  true,
+	"run":     true,
+	"forfeit": true,
+	"party":   true,
+	"inspect": true,
+	"learn":   true,
+}
+
+func commandBattle(conf *config, args []string) error {
+	if conf.battle != nil {
+		if len(args) > 0 {
+			return errors.New("You're already in a battle!")
+		}
+		printBattleStatus(conf.out, conf.battle)
+		return nil
+	}
+
+	if len(args) == 0 {
+		return errors.New("Usage: battle wild [pokemon] [level=N] | battle trainer <name> <pokemon[:level]>... [seed=N]")
+	}
+
+	options, rest, err := parseBattleOptions(args[1:])
+	if err != nil {
+		return err
+	}
+
+	team, playerSide, err := playerBattleSide(conf)
+	if err != nil {
+		return err
+	}
+
+	// One seed picks the opponent and plays out the battle, so the same seed
+	// and actions always give the same fight
+	seed := time.Now().UnixNano()
+	if value, ok := options["seed"]; ok {
+		seed = int64(value)
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	var opponent *battle.Side
+	var opponents []Pokemon
+	wild := false
+	switch args[0] {
+	case "wild":
+		wild = true
+		opponent, opponents, err = wildBattleSide(conf, rest, options["level"], rng.Intn)
+	case "trainer":
+		opponent, opponents, err = conf.api.trainerBattleSide(rest, averageLevel(team), rng.Intn)
+	default:
+		return fmt.Errorf("Unknown battle type %s", args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, pokemon := range opponents {
+		conf.pokedex.MarkSeen(pokemon.Species.Name)
+	}
+
+	chart, err := conf.api.loadTypeChart(moveTypes(playerSide, opponent))
+	if err != nil {
+		return err
+	}
+
+	b, err := battle.New(playerSide, opponent, wild, chart, seed)
+	if err != nil {
+		return err
+	}
+	conf.battle = &activeBattle{
+		battle:    b,
+		team:      team,
+		opponents: opponents,
+	}
+
+	if wild {
+		fmt.Fprintf(conf.out, "A wild %s appeared!\n", opponent.ActivePokemon().Name)
+	} else {
+		fmt.Fprintf(conf.out, "%s wants to battle!\n", opponent.Name)
+		fmt.Fprintf(conf.out, "%s sent out %s!\n", opponent.Name, opponent.ActivePokemon().Name)
+	}
+	fmt.Fprintf(conf.out, "Go, %s!\n", playerSide.ActivePokemon().Name)
+	printBattleStatus(conf.out, conf.battle)
+
+	return nil
+}
+
+func commandFight(conf *config, args []string) error {
+	if conf.battle == nil {
+		return errors.New("You're not in a battle")
+	}
+	if len(args) == 0 {
+		return errors.New("Must pass a move name or number to the fight command")
+	}
+
+	active := conf.battle.battle.Player.ActivePokemon()
+	index := -1
+	if num, err := strconv.Atoi(args[0]); err == nil {
+		index = num - 1
+	} else {
+		for i, slot := range active.Moves {
+			if slot.Move.Name == args[0] {
+				index = i
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("%s doesn't know %s", active.Name, args[0])
+		}
+	}
+
+	return takeBattleTurn(conf, battle.Action{Kind: battle.Fight, Index: index})
+}
+
+func commandSwitch(conf *config, args []string) error {
+	if conf.battle == nil {
+		return errors.New("You're not in a battle")
+	}
+	if len(args) == 0 {
+		return errors.New("Must pass a party slot or pokemon to the switch command")
+	}
+
+	index := -1
+	if num, err := strconv.Atoi(args[0]); err == nil {
+		index = num - 1
+	} else {
+		caught, err := conf.pokedex.Find(args[0])
+		if err != nil {
+			return err
+		}
+		for i, member := range conf.battle.team {
+			if member.ID == caught.ID {
+				index = i
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("%s isn't in your party", caught.Name())
+		}
+	}
+
+	return takeBattleTurn(conf, battle.Action{Kind: battle.Switch, Index: index})
+}
+
+func commandRun(conf *config, args []string) error {
+	if conf.battle == nil {
+		return errors.New("You're not in a battle")
+	}
+	return takeBattleTurn(conf, battle.Action{Kind: battle.Run})
+}
+
+func commandForfeit(conf *config, args []string) error {
+	if conf.battle == nil {
+		return errors.New("You're not in a battle")
+	}
+	return takeBattleTurn(conf, battle.Action{Kind: battle.Forfeit})
+}
+
+func takeBattleTurn(conf *config, action battle.Action) error {
+	b := conf.battle.battle
+	log, err := b.Turn(action)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range log {
+		fmt.Fprintln(conf.out, line)
+	}
+
+	// A reward that fails to save mustn't leave a finished battle in place,
+	// since it blocks every other command
+	rewardErr := rewardDefeats(conf, conf.battle)
+
+	switch b.Result {
+	case battle.Ongoing:
+		if rewardErr != nil {
+			return rewardErr
+		}
+		if b.MustSwitch {
+			fmt.Fprintln(conf.out, "Choose a pokemon to send out with switch <slot>")
+		}
+		printBattleStatus(conf.out, conf.battle)
+		return nil
+	case battle.Won:
+		fmt.Fprintln(conf.out, "You won the battle!")
+		awardPrize(conf, battlePrize(b.Wild, rand.Intn(100)))
+	case battle.Lost:
+		fmt.Fprintln(conf.out, "You lost the battle...")
+	}
+
+	conf.battle = nil
+	return rewardErr
+}
+
+// rewardDefeats gives out experience and EVs for opponents that fainted since
+// the last turn, updating the battling pokemon if they level up.
+func rewardDefeats(conf *config, active *activeBattle) error {
+	b := active.battle
+	for ; active.rewarded < len(b.Defeats); active.rewarded++ {
+		defeat := b.Defeats[active.rewarded]
+		defeated := active.opponents[defeat.Opponent]
+		level := b.Opponent.Team[defeat.Opponent].Level
+		exp := defeatExperience(defeated.BaseExperience, level, !b.Wild, len(defeat.Participants))
+
+		for _, index := range defeat.Participants {
+			caught := active.team[index]
+			gainEVs(caught, defeated)
+			levels, err := gainExperience(conf, caught, exp)
+			if err != nil {
+				return err
+			}
+			if levels == 0 {
+				continue
+			}
+
+			pokemon, err := conf.api.getPokemon(caught.Species)
+			if err != nil {
+				return err
+			}
+			combatant := b.Player.Team[index]
+			stats := calcStats(pokemon, caught)
+			combatant.HP += stats["hp"] - combatant.Stats["hp"]
+			combatant.Stats = stats
+			combatant.Level = caught.Level
+		}
+	}
+
+	return nil
+}
+
+func printBattleStatus(out io.Writer, active *activeBattle) {
+	b := active.battle
+	player := b.Player.ActivePokemon()
+	opponent := b.Opponent.ActivePokemon()
+
+	fmt.Fprintf(out, "%s Lv.%d %d/%d HP vs %s Lv.%d %d/%d HP\n",
+		player.Name, player.Level, player.HP, player.Stats["hp"],
+		opponent.Name, opponent.Level, opponent.HP, opponent.Stats["hp"])
+
+	fmt.Fprintln(out, "Moves:")
+	for i, slot := range player.Moves {
+		fmt.Fprintf(out, "\t%d. %s (%s, %d/%d PP)\n", i+1, slot.Move.Name, slot.Move.Type, slot.PP, slot.Move.PP)
+	}
+	fmt.Fprintln(out, "Team:")
+	for i, member := range b.Player.Team {
+		status := fmt.Sprintf("%d/%d HP", member.HP, member.Stats["hp"])
+		if member.Fainted() {
+			status = "fainted"
+		}
+		fmt.Fprintf(out, "\t%d. %s Lv.%d %s\n", i+1, member.Name, member.Level, status)
+	}
+}
+
+// parseBattleOptions splits key=value options from the other arguments. An
+// option is only in the map when it was given, so seed=0 is still a seed.
+func parseBattleOptions(args []string) (map[string]int, []string, error) {
+	options := map[string]int{}
+	rest := []string{}
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			rest = append(rest, arg)
+			continue
+		}
+		if key != "level" && key != "seed" {
+			return nil, nil, fmt.Errorf("Unknown option %s", key)
+		}
+		num, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid value for %s: %s", key, value)
+		}
+		if key == "level" && (num < 1 || num > maxLevel) {
+			return nil, nil, fmt.Errorf("Invalid level %d, it must be between 1 and %d", num, maxLevel)
+		}
+		options[key] = num
+	}
+	return options, rest, nil
+}
+
+func playerBattleSide(conf *config) ([]*CaughtPokemon, *battle.Side, error) {
+	if len(conf.storage.Party) == 0 {
+		return nil, nil, errors.New("You don't have any pokemon in your party!")
+	}
+
+	team := []*CaughtPokemon{}
+	side := battle.Side{Name: "You"}
+	for _, id := range conf.storage.Party {
+		caught := conf.pokedex.Caught[id]
+		combatant, err := conf.api.newCombatant(caught)
+		if err != nil {
+			return nil, nil, err
+		}
+		team = append(team, caught)
+		side.Team = append(side.Team, combatant)
+	}
+
+	return team, &side, nil
+}
+
+// wildBattleSide sets up a wild pokemon, either the one asked for or a random
+// encounter from the area explored last. A level of 0 means one wasn't given.
+func wildBattleSide(conf *config, args []string, level int, intn func(n int) int) (*battle.Side, []Pokemon, error) {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		if len(conf.currentArea) == 0 {
+			return nil, nil, errors.New("Explore an area first or pass a pokemon to battle")
+		}
+		var err error
+		name, err = conf.api.randomEncounter(conf.currentArea, intn)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if level == 0 {
+		level = conf.api.encounterLevel(conf.currentArea, name, intn)
+	}
+	if level < 1 || level > maxLevel {
+		return nil, nil, fmt.Errorf("Invalid level %d, it must be between 1 and %d", level, maxLevel)
+	}
+
+	combatant, pokemon, err := conf.api.newOpponentCombatant(name, level, intn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	side := battle.Side{
+		Name: "Wild " + combatant.Name,
+		Team: []*battle.Combatant{combatant},
+	}
+	return &side, []Pokemon{pokemon}, nil
+}
+
+// trainerBattleSide builds a trainer's team from pokemon[:level] arguments,
+// using defaultLevel when a level isn't given.
+func (c *apiClient) trainerBattleSide(args []string, defaultLevel int, intn func(n int) int) (*battle.Side, []Pokemon, error) {
+	if len(args) < 2 {
+		return nil, nil, errors.New("Usage: battle trainer <name> <pokemon[:level]>...")
+	}
+	if len(args)-1 > maxPartySize {
+		return nil, nil, fmt.Errorf("A trainer can have at most %d pokemon", maxPartySize)
+	}
+
+	side := battle.Side{Name: args[0]}
+	team := []Pokemon{}
+	for _, arg := range args[1:] {
+		name, levelText, hasLevel := strings.Cut(arg, ":")
+		level := defaultLevel
+		if hasLevel {
+			var err error
+			level, err = strconv.Atoi(levelText)
+			if err != nil || level < 1 || level > maxLevel {
+				return nil, nil, fmt.Errorf("Invalid level for %s: %s", name, levelText)
+			}
+		}
+
+		combatant, pokemon, err := c.newOpponentCombatant(name, level, intn)
+		if err != nil {
+			return nil, nil, err
+		}
+		side.Team = append(side.Team, combatant)
+		team = append(team, pokemon)
+	}
+
+	return &side, team, nil
+}
+
+func (c *apiClient) newOpponentCombatant(name string, level int, intn func(n int) int) (*battle.Combatant, Pokemon, error) {
+	pokemon, err := c.getPokemon(name)
+	if err != nil {
+		return nil, pokemon, err
+	}
+
+	individual, err := c.newIndividual(pokemon, level, intn)
+	if err != nil {
+		return nil, pokemon, err
+	}
+
+	combatant, err := c.combatantFor(pokemon, individual)
+	return combatant, pokemon, err
+}
+
+func (c *apiClient) newCombatant(caught *CaughtPokemon) (*battle.Combatant, error) {
+	pokemon, err := c.getPokemon(caught.Species)
+	if err != nil {
+		return nil, err
+	}
+	return c.combatantFor(pokemon, caught)
+}
+
+func (c *apiClient) combatantFor(pokemon Pokemon, individual *CaughtPokemon) (*battle.Combatant, error) {
+	moveNames := individual.Moves
+	if len(moveNames) == 0 {
+		moveNames = defaultMoves(pokemon, individual.Level)
+	}
+
+	moves := []battle.Move{}
+	for _, name := range moveNames {
+		move, err := c.getMove(name)
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, move.battleMove())
+	}
+
+	types := []string{}
+	for _, poketype := range pokemon.Types {
+		types = append(types, poketype.Type.Name)
+	}
+
+	return battle.NewCombatant(individual.Name(), individual.Level, types, calcStats(pokemon, individual), moves), nil
+}
+
+// randomEncounter picks a pokemon from an area, weighted by how likely each
+// one is to appear.
+func (c *apiClient) randomEncounter(area string, intn func(n int) int) (string, error) {
+	explore, err := c.getLocationArea(area)
+	if err != nil {
+		return "", err
+	}
+
+	total := 0
+	weights := []int{}
+	for _, encounter := range explore.PokemonEncounters {
+		weight := 0
+		for _, version := range encounter.VersionDetails {
+			weight = max(weight, version.MaxChance)
+		}
+		weight = max(weight, 1)
+		weights = append(weights, weight)
+		total += weight
+	}
+	if total == 0 {
+		return "", errors.New("No pokemon in the area!")
+	}
+
+	roll := intn(total)
+	for i, weight := range weights {
+		if roll < weight {
+			return explore.PokemonEncounters[i].Pokemon.Name, nil
+		}
+		roll -= weight
+	}
+
+	return "", errors.New("No pokemon in the area!")
+}
+
+func averageLevel(team []*CaughtPokemon) int {
+	if len(team) == 0 {
+		return defaultCatchLevel
+	}
+	total := 0
+	for _, member := range team {
+		total += member.Level
+	}
+	return total / len(team)
+}
+
+func moveTypes(sides ...*battle.Side) []string {
+	types := []string{}
+	for _, side := range sides {
+		for _, combatant := range side.Team {
+			for _, slot := range combatant.Moves {
+				types = append(types, slot.Move.Type)
+			}
+		}
+	}
+	return types
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/mikeheiberger/pokedexcli/internal/battle"
+)
+
+func TestParseBattleOptions(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected map[string]int
+		rest     []string
+		fails    bool
+	}{
+		{args: []string{"pikachu", "level=5"}, expected: map[string]int{"level": 5}, rest: []string{"pikachu"}},
+		{args: []string{"seed=0"}, expected: map[string]int{"seed": 0}, rest: []string{}},
+		{args: []string{"level=100", "seed=-3"}, expected: map[string]int{"level": 100, "seed": -3}, rest: []string{}},
+		{args: []string{"level=0"}, fails: true},
+		{args: []string{"level=101"}, fails: true},
+		{args: []string{"level=-5"}, fails: true},
+		{args: []string{"level=five"}, fails: true},
+		{args: []string{"speed=3"}, fails: true},
+	}
+
+	for _, c := range cases {
+		options, rest, err := parseBattleOptions(c.args)
+		if c.fails {
+			if err == nil {
+				t.Errorf("%v: expected an error", c.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", c.args, err)
+		}
+		if len(options) != len(c.expected) {
+			t.Errorf("%v: actual %v != expected %v", c.args, options, c.expected)
+		}
+		for key, value := range c.expected {
+			if actual, ok := options[key]; !ok || actual != value {
+				t.Errorf("%v: actual %v != expected %v", c.args, options, c.expected)
+			}
+		}
+		if strings.Join(rest, ",") != strings.Join(c.rest, ",") {
+			t.Errorf("%v: actual rest %v != expected %v", c.args, rest, c.rest)
+		}
+	}
+}
+
+func TestTakeBattleTurnRewardError(t *testing.T) {
+	// The species response can't be decoded, so the experience for the win
+	// fails after the battle has been decided
+	conf, _ := newTestConfig(t, map[string]string{
+		"https://pokeapi.co/api/v2/pokemon/pikachu/":    `{"name": "pikachu", "species": {"name": "pikachu", "url": "https://pokeapi.co/api/v2/pokemon-species/25/"}}`,
+		"https://pokeapi.co/api/v2/pokemon-species/25/": `not json`,
+	})
+
+	tackle := battle.Move{Name: "tackle", Type: "normal", Class: "physical", Power: 40, PP: 35}
+	player := &battle.Side{Team: []*battle.Combatant{
+		battle.NewCombatant("pikachu", 5, []string{"electric"}, map[string]int{"hp": 20, "attack": 50, "speed": 90}, []battle.Move{tackle}),
+	}}
+	opponent := &battle.Side{Team: []*battle.Combatant{
+		battle.NewCombatant("rattata", 2, []string{"normal"}, map[string]int{"hp": 1, "defense": 5, "speed": 1}, []battle.Move{tackle}),
+	}}
+	b, err := battle.New(player, opponent, true, battle.TypeChart{}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conf.battle = &activeBattle{
+		battle:    b,
+		team:      []*CaughtPokemon{{Species: "pikachu", Level: 5}},
+		opponents: []Pokemon{{Name: "rattata", BaseExperience: 51}},
+	}
+
+	err = takeBattleTurn(conf, battle.Action{Kind: battle.Fight})
+	if err == nil {
+		t.Errorf("expected the reward error to be reported")
+	}
+	if b.Result != battle.Won || conf.battle != nil {
+		t.Errorf("expected the won battle to be over, got result %v", b.Result)
+	}
+}
+
+func TestWildBattleSideSeed(t *testing.T) {
+	conf, _ := newTestConfig(t, map[string]string{
+		"https://pokeapi.co/api/v2/location-area/viridian-forest-area/": `{
+			"name": "viridian-forest-area",
+			"pokemon_encounters": [
+				{"pokemon": {"name": "caterpie"}, "version_details": [
+					{"max_chance": 50, "encounter_details": [{"min_level": 3, "max_level": 5}]}
+				]},
+				{"pokemon": {"name": "pikachu"}, "version_details": [
+					{"max_chance": 50, "encounter_details": [{"min_level": 3, "max_level": 5}]}
+				]}
+			]
+		}`,
+		"https://pokeapi.co/api/v2/pokemon/caterpie/": `{
+			"name": "caterpie",
+			"species": {"name": "caterpie", "url": "https://pokeapi.co/api/v2/pokemon-species/10/"},
+			"stats": [{"base_stat": 45, "stat": {"name": "hp"}}, {"base_stat": 45, "stat": {"name": "speed"}}]
+		}`,
+		"https://pokeapi.co/api/v2/pokemon/pikachu/": `{
+			"name": "pikachu",
+			"species": {"name": "pikachu", "url": "https://pokeapi.co/api/v2/pokemon-species/25/"},
+			"stats": [{"base_stat": 35, "stat": {"name": "hp"}}, {"base_stat": 90, "stat": {"name": "speed"}}]
+		}`,
+		"https://pokeapi.co/api/v2/pokemon-species/10/": `{
+			"name": "caterpie",
+			"gender_rate": 4,
+			"growth_rate": {"name": "medium", "url": "https://pokeapi.co/api/v2/growth-rate/2/"}
+		}`,
+		"https://pokeapi.co/api/v2/pokemon-species/25/": `{
+			"name": "pikachu",
+			"gender_rate": 4,
+			"growth_rate": {"name": "medium", "url": "https://pokeapi.co/api/v2/growth-rate/2/"}
+		}`,
+		"https://pokeapi.co/api/v2/growth-rate/2/": `{"name": "medium", "levels": []}`,
+	})
+	conf.currentArea = "viridian-forest-area"
+
+	describe := func(seed int64) string {
+		side, _, err := wildBattleSide(conf, []string{}, 0, rand.New(rand.NewSource(seed)).Intn)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		combatant := side.ActivePokemon()
+		return fmt.Sprintf("%s Lv.%d %v", combatant.Name, combatant.Level, combatant.Stats)
+	}
+
+	for seed := int64(0); seed < 5; seed++ {
+		first, second := describe(seed), describe(seed)
+		if first != second {
+			t.Errorf("seed %d: %s != %s", seed, first, second)
+		}
+	}
+}
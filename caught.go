@@ -0,0 +1,390 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CaughtPokemon is one individual pokemon owned by the trainer. The species
+// data itself is always looked up from the API by Species, which is the
+// pokemon's name and differs from its species for forms such as
+// deoxys-normal. SpeciesName is what the pokedex counts it as.
+type CaughtPokemon struct {
+	ID          int            `json:"id"`
+	Species     string         `json:"species"`
+	SpeciesName string         `json:"species_name,omitempty"`
+	Nickname    string         `json:"nickname,omitempty"`
+	Level       int            `json:"level"`
+	IVs         map[string]int `json:"ivs"`
+	EVs         map[string]int `json:"evs"`
+	Nature      string         `json:"nature"`
+	Gender      string         `json:"gender"`
+	Shiny       bool           `json:"shiny"`
+	CaughtAt    time.Time      `json:"caught_at"`
+	Location    string         `json:"location,omitempty"`
+	Moves       []string       `json:"moves"`
+	Ability     string         `json:"ability,omitempty"`
+	Friendship  int            `json:"friendship"`
+	Experience  int            `json:"experience"`
+
+	// PendingMoves are moves learned on leveling up that are waiting for
+	// the player to choose a move to forget.
+	PendingMoves []string `json:"pending_moves,omitempty"`
+}
+
+type Pokedex struct {
+	NextID int                    `json:"next_id"`
+	Caught map[int]*CaughtPokemon `json:"caught"`
+
+	// Seen and SpeciesCaught track pokedex completion by species name,
+	// SpeciesCaught holding when each species was first caught.
+	Seen          map[string]bool      `json:"seen"`
+	SpeciesCaught map[string]time.Time `json:"species_caught"`
+}
+
+type PokemonSpecies struct {
+	ID             int           `json:"id"`
+	Name           string        `json:"name"`
+	GenderRate     int           `json:"gender_rate"`
+	BaseHappiness  int           `json:"base_happiness"`
+	GrowthRate     namedResource `json:"growth_rate"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	EvolvesFromSpecies *namedResource `json:"evolves_from_species"`
+	Varieties          []struct {
+		IsDefault bool          `json:"is_default"`
+		Pokemon   namedResource `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+// statNames are the API stat names in the order the games list them.
+var statNames = []string{
+	"hp",
+	"attack",
+	"defense",
+	"special-attack",
+	"special-defense",
+	"speed",
+}
+
+var natureNames = []string{
+	"hardy", "lonely", "brave", "adamant", "naughty",
+	"bold", "docile", "relaxed", "impish", "lax",
+	"timid", "hasty", "serious", "jolly", "naive",
+	"modest", "mild", "quiet", "bashful", "rash",
+	"calm", "gentle", "sassy", "careful", "quirky",
+}
+
+const shinyOdds = 4096
+
+const defaultCatchLevel = 5
+
+func NewPokedex() *Pokedex {
+	return &Pokedex{
+		NextID:        1,
+		Caught:        map[int]*CaughtPokemon{},
+		Seen:          map[string]bool{},
+		SpeciesCaught: map[string]time.Time{},
+	}
+}
+
+func (c *CaughtPokemon) Name() string {
+	if len(c.Nickname) > 0 {
+		return c.Nickname
+	}
+	return c.Species
+}
+
+// dexSpecies is the species the pokedex records this pokemon under. Saves
+// from before SpeciesName was recorded fall back to the pokemon's name.
+func (c *CaughtPokemon) dexSpecies() string {
+	if len(c.SpeciesName) > 0 {
+		return c.SpeciesName
+	}
+	return c.Species
+}
+
+// isSpecies reports whether the pokemon is of a species, by its species or
+// its pokemon name.
+func (c *CaughtPokemon) isSpecies(species string) bool {
+	return c.Species == species || c.dexSpecies() == species
+}
+
+func (c *CaughtPokemon) String() string {
+	desc := fmt.Sprintf("#%d %s", c.ID, c.Species)
+	if len(c.Nickname) > 0 {
+		desc += fmt.Sprintf(" \"%s\"", c.Nickname)
+	}
+	desc += fmt.Sprintf(" Lv.%d", c.Level)
+	if c.Shiny {
+		desc += " *shiny*"
+	}
+	return desc
+}
+
+// Add stores the pokemon under the next free ID and returns that ID.
+func (p *Pokedex) Add(c *CaughtPokemon) int {
+	c.ID = p.NextID
+	p.NextID++
+	p.Caught[c.ID] = c
+	return c.ID
+}
+
+func (p *Pokedex) Remove(id int) {
+	delete(p.Caught, id)
+}
+
+// Sorted returns every caught pokemon ordered by ID.
+func (p *Pokedex) Sorted() []*CaughtPokemon {
+	list := make([]*CaughtPokemon, 0, len(p.Caught))
+	for _, c := range p.Caught {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// Find looks a caught pokemon up by ID, nickname or species name. Species
+// names only match when exactly one individual of that species is owned.
+func (p *Pokedex) Find(param string) (*CaughtPokemon, error) {
+	if id, err := strconv.Atoi(strings.TrimPrefix(param, "#")); err == nil {
+		c, ok := p.Caught[id]
+		if !ok {
+			return nil, fmt.Errorf("You don't have a pokemon with ID %d", id)
+		}
+		return c, nil
+	}
+
+	matches := []*CaughtPokemon{}
+	for _, c := range p.Sorted() {
+		if c.Nickname == param {
+			return c, nil
+		}
+		if c.isSpecies(param) {
+			matches = append(matches, c)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, errors.New("You have not caught that pokemon")
+	}
+	if len(matches) > 1 {
+		ids := []string{}
+		for _, c := range matches {
+			ids = append(ids, fmt.Sprintf("#%d", c.ID))
+		}
+		return nil, fmt.Errorf("You have more than one %s, use an ID: %s", param, strings.Join(ids, ", "))
+	}
+
+	return matches[0], nil
+}
+
+func (c *apiClient) getPokemon(name string) (Pokemon, error) {
+	const baseUrl = "https://pokeapi.co/api/v2/pokemon/"
+
+	var pokemon Pokemon
+	jsonData, err := c.getJsonFromCacheOrServer(baseUrl + name + "/")
+	if err != nil {
+		return pokemon, err
+	}
+
+	err = json.Unmarshal(jsonData, &pokemon)
+	if err != nil {
+		return pokemon, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+
+	return pokemon, nil
+}
+
+// speciesOf finds the species a pokemon belongs to. If it can't be looked up
+// the pokemon's name is used, which is the species name for most pokemon.
+func (c *apiClient) speciesOf(name string) string {
+	pokemon, err := c.getPokemon(name)
+	if err != nil || len(pokemon.Species.Name) == 0 {
+		return name
+	}
+	return pokemon.Species.Name
+}
+
+func (c *apiClient) getSpecies(url string) (PokemonSpecies, error) {
+	var species PokemonSpecies
+	jsonData, err := c.getJsonFromCacheOrServer(url)
+	if err != nil {
+		return species, err
+	}
+
+	err = json.Unmarshal(jsonData, &species)
+	if err != nil {
+		return species, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+
+	return species, nil
+}
+
+func (c *apiClient) getSpeciesByName(name string) (PokemonSpecies, error) {
+	return c.getSpecies("https://pokeapi.co/api/v2/pokemon-species/" + name + "/")
+}
+
+// defaultVariety is the name of the pokemon resource for a species, which
+// differs from the species name for pokemon with forms like wormadam.
+func (s PokemonSpecies) defaultVariety() string {
+	for _, variety := range s.Varieties {
+		if variety.IsDefault {
+			return variety.Pokemon.Name
+		}
+	}
+	return s.Name
+}
+
+// newCaughtPokemon rolls the individual values for a freshly caught pokemon.
+func newCaughtPokemon(conf *config, pokemon Pokemon) (*CaughtPokemon, error) {
+	caught, err := conf.api.newIndividual(pokemon, conf.api.encounterLevel(conf.currentArea, pokemon.Name, rand.Intn), rand.Intn)
+	if err != nil {
+		return nil, err
+	}
+
+	caught.CaughtAt = time.Now()
+	caught.Location = conf.currentArea
+	return caught, nil
+}
+
+// newIndividual rolls a random individual of a species at the given level,
+// such as a wild pokemon or one owned by another trainer. intn makes the
+// rolls, so a seeded battle gets the same opponent every time.
+func (c *apiClient) newIndividual(pokemon Pokemon, level int, intn func(n int) int) (*CaughtPokemon, error) {
+	species, err := c.getSpecies(pokemon.Species.URL)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := c.getGrowthRate(species.GrowthRate.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	individual := CaughtPokemon{
+		Species:     pokemon.Name,
+		SpeciesName: pokemon.Species.Name,
+		Level:       level,
+		IVs:         map[string]int{},
+		EVs:         map[string]int{},
+		Nature:      natureNames[intn(len(natureNames))],
+		Gender:      rollGender(species.GenderRate, intn),
+		Shiny:       intn(shinyOdds) == 0,
+		Moves:       defaultMoves(pokemon, level),
+		Ability:     rollAbility(pokemon, intn),
+
+		Friendship: species.BaseHappiness,
+		Experience: rate.experienceFor(level),
+	}
+
+	for _, stat := range statNames {
+		individual.IVs[stat] = intn(maxIV + 1)
+		individual.EVs[stat] = 0
+	}
+
+	return &individual, nil
+}
+
+// rollGender uses the API gender rate, which is the chance of a female in
+// eighths, or -1 for genderless species.
+func rollGender(genderRate int, intn func(n int) int) string {
+	if genderRate < 0 {
+		return "genderless"
+	}
+	if intn(8) < genderRate {
+		return "female"
+	}
+	return "male"
+}
+
+// encounterLevel picks a level inside the range the pokemon appears at in the
+// given area, falling back to defaultCatchLevel when that isn't known.
+func (c *apiClient) encounterLevel(area string, name string, intn func(n int) int) int {
+	if len(area) == 0 {
+		return defaultCatchLevel
+	}
+
+	explore, err := c.getLocationArea(area)
+	if err != nil {
+		return defaultCatchLevel
+	}
+
+	minLevel, maxLevel := 0, 0
+	for _, encounter := range explore.PokemonEncounters {
+		if encounter.Pokemon.Name != name {
+			continue
+		}
+		for _, version := range encounter.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				if minLevel == 0 || detail.MinLevel < minLevel {
+					minLevel = detail.MinLevel
+				}
+				if detail.MaxLevel > maxLevel {
+					maxLevel = detail.MaxLevel
+				}
+			}
+		}
+	}
+
+	if minLevel == 0 || maxLevel < minLevel {
+		return defaultCatchLevel
+	}
+
+	return minLevel + intn(maxLevel-minLevel+1)
+}
+
+func commandRename(conf *config, args []string) error {
+	if len(args) < 2 {
+		return errors.New("Usage: rename <pokemon> <nickname>")
+	}
+	// Commands look a pokemon up by a single argument, so a nickname with a
+	// space in it could never be found again
+	if len(args) > 2 {
+		return errors.New("A nickname can't have spaces in it")
+	}
+
+	caught, err := conf.pokedex.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	nickname := args[1]
+	if _, err := strconv.Atoi(strings.TrimPrefix(nickname, "#")); err == nil {
+		return errors.New("A nickname can't be a number")
+	}
+
+	fmt.Fprintf(conf.out, "%s is now known as %s\n", caught.Name(), nickname)
+	caught.Nickname = nickname
+
+	return nil
+}
+
+func commandRelease(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Must pass a pokemon to the release command")
+	}
+
+	caught, err := conf.pokedex.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	if conf.storage.InParty(caught.ID) && len(conf.storage.Party) == 1 {
+		return errors.New("You can't release your last pokemon")
+	}
+
+	conf.pokedex.Remove(caught.ID)
+	conf.storage.Remove(caught.ID)
+	fmt.Fprintf(conf.out, "%s was released. Bye, %s!\n", caught, caught.Name())
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPokedexFind(t *testing.T) {
+	dex := NewPokedex()
+	dex.Add(&CaughtPokemon{Species: "pikachu", Nickname: "sparky"})
+	dex.Add(&CaughtPokemon{Species: "rattata"})
+	dex.Add(&CaughtPokemon{Species: "rattata"})
+	dex.Add(&CaughtPokemon{Species: "pidgey"})
+
+	cases := []struct {
+		param    string
+		expected int
+		err      string
+	}{
+		{param: "1", expected: 1},
+		{param: "#3", expected: 3},
+		{param: "sparky", expected: 1},
+		{param: "pidgey", expected: 4},
+		{param: "pikachu", expected: 1},
+		{param: "rattata", err: "You have more than one rattata, use an ID: #2, #3"},
+		{param: "9", err: "You don't have a pokemon with ID 9"},
+		{param: "mew", err: "You have not caught that pokemon"},
+	}
+
+	for _, c := range cases {
+		actual, err := dex.Find(c.param)
+		if len(c.err) > 0 {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("%s: actual error %v != expected %s", c.param, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.param, err)
+			continue
+		}
+		if actual.ID != c.expected {
+			t.Errorf("%s: actual #%d != expected #%d", c.param, actual.ID, c.expected)
+		}
+	}
+}
+
+func TestNewCaughtPokemon(t *testing.T) {
+	conf, _ := newTestConfig(t, map[string]string{
+		"https://pokeapi.co/api/v2/location-area/viridian-forest-area/": `{
+			"name": "viridian-forest-area",
+			"pokemon_encounters": [{"pokemon": {"name": "pikachu"}, "version_details": [
+				{"encounter_details": [{"min_level": 3, "max_level": 5}]}
+			]}]
+		}`,
+		"https://pokeapi.co/api/v2/pokemon-species/25/": `{
+			"name": "pikachu",
+			"gender_rate": 4,
+			"base_happiness": 70,
+			"growth_rate": {"name": "medium", "url": "https://pokeapi.co/api/v2/growth-rate/2/"}
+		}`,
+		"https://pokeapi.co/api/v2/growth-rate/2/": `{"name": "medium", "levels": [
+			{"level": 3, "experience": 27}, {"level": 4, "experience": 64}, {"level": 5, "experience": 125}
+		]}`,
+	})
+	conf.currentArea = "viridian-forest-area"
+
+	var pokemon Pokemon
+	err := json.Unmarshal([]byte(`{
+		"name": "pikachu",
+		"species": {"name": "pikachu", "url": "https://pokeapi.co/api/v2/pokemon-species/25/"},
+		"abilities": [{"ability": {"name": "static"}, "is_hidden": false}]
+	}`), &pokemon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	caught, err := newCaughtPokemon(conf, pokemon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if caught.Species != "pikachu" || caught.Location != "viridian-forest-area" || caught.CaughtAt.IsZero() {
+		t.Errorf("unexpected pokemon %+v", caught)
+	}
+	if caught.Level < 3 || caught.Level > 5 {
+		t.Errorf("actual level %d != expected 3 to 5", caught.Level)
+	}
+	if caught.Friendship != 70 || caught.Ability != "static" || !containsString(natureNames, caught.Nature) {
+		t.Errorf("unexpected friendship %d, ability %s or nature %s", caught.Friendship, caught.Ability, caught.Nature)
+	}
+	if caught.Experience != map[int]int{3: 27, 4: 64, 5: 125}[caught.Level] {
+		t.Errorf("actual experience %d doesn't match level %d", caught.Experience, caught.Level)
+	}
+	for _, stat := range statNames {
+		if caught.IVs[stat] < 0 || caught.IVs[stat] > maxIV || caught.EVs[stat] != 0 {
+			t.Errorf("%s: unexpected IV %d or EV %d", stat, caught.IVs[stat], caught.EVs[stat])
+		}
+	}
+}
+
+func TestCommandRelease(t *testing.T) {
+	conf, out := newTestConfig(t, nil)
+	conf.storage.Store(conf.pokedex.Add(&CaughtPokemon{Species: "pikachu"}))
+	conf.storage.Store(conf.pokedex.Add(&CaughtPokemon{Species: "pidgey"}))
+
+	err := commandRelease(conf, []string{"pidgey"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := conf.pokedex.Caught[2]; ok || len(conf.storage.Party) != 1 {
+		t.Errorf("expected pidgey to be gone, party %v", conf.storage.Party)
+	}
+	if !strings.Contains(out.String(), "Bye, pidgey!") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	if err := commandRelease(conf, []string{"pikachu"}); err == nil {
+		t.Errorf("expected an error releasing the last pokemon in the party")
+	}
+	if err := commandRelease(conf, []string{"pidgey"}); err == nil {
+		t.Errorf("expected an error releasing a pokemon that's gone")
+	}
+	if err := commandRelease(conf, []string{}); err == nil {
+		t.Errorf("expected an error without a pokemon")
+	}
+}
+
+func TestCommandRename(t *testing.T) {
+	conf, out := newTestConfig(t, nil)
+	conf.storage.Store(conf.pokedex.Add(&CaughtPokemon{Species: "pikachu"}))
+
+	err := commandRename(conf, []string{"pikachu", "sparky"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := conf.pokedex.Find("sparky"); err != nil {
+		t.Errorf("expected to find the pokemon by its nickname: %v", err)
+	}
+	if !strings.Contains(out.String(), "pikachu is now known as sparky") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	if err := commandRename(conf, []string{"sparky", "mr", "sparky"}); err == nil {
+		t.Errorf("expected an error for a nickname with a space")
+	}
+	if err := commandRename(conf, []string{"sparky", "#7"}); err == nil {
+		t.Errorf("expected an error for a nickname that's a number")
+	}
+	if caught, _ := conf.pokedex.Find("#1"); caught.Nickname != "sparky" {
+		t.Errorf("actual %s != expected sparky", caught.Nickname)
+	}
+}
+
+func TestDexSpecies(t *testing.T) {
+	conf, _ := newTestConfig(t, map[string]string{
+		"https://pokeapi.co/api/v2/location-area/space-area/": `{
+			"name": "space-area",
+			"pokemon_encounters": [{"pokemon": {"name": "deoxys-normal"}}]
+		}`,
+		"https://pokeapi.co/api/v2/pokemon/deoxys-normal/": `{"name": "deoxys-normal", "species": {"name": "deoxys"}}`,
+	})
+
+	err := commandExplore(conf, []string{"space-area"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conf.pokedex.Status("deoxys") != "seen" || conf.pokedex.Seen["deoxys-normal"] {
+		t.Errorf("expected deoxys to be seen by its species, got %v", conf.pokedex.Seen)
+	}
+
+	conf.pokedex.Add(&CaughtPokemon{Species: "deoxys-normal", SpeciesName: "deoxys"})
+	conf.pokedex.Add(&CaughtPokemon{Species: "pikachu"})
+	err = writeSave(conf.saveFile, conf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	save, err := readSave(conf.saveFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if save.Pokedex.Status("deoxys") != "caught" || save.Pokedex.Status("pikachu") != "caught" {
+		t.Errorf("expected both species to be caught, got %v", save.Pokedex.SpeciesCaught)
+	}
+	if _, ok := save.Pokedex.SpeciesCaught["deoxys-normal"]; ok {
+		t.Errorf("expected the form not to be counted as a species")
+	}
+	if save.Pokedex.countOwned("deoxys") != 1 || save.Pokedex.countOwned("deoxys-normal") != 0 {
+		t.Errorf("actual %d deoxys owned != expected 1", save.Pokedex.countOwned("deoxys"))
+	}
+	if caught, err := save.Pokedex.Find("deoxys"); err != nil || caught.ID != 1 {
+		t.Errorf("expected to find deoxys-normal by its species, got %v", err)
+	}
+}
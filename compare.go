@@ -0,0 +1,248 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// compareSubject is one column of the compare table, either a caught pokemon
+// or a species.
+type compareSubject struct {
+	label   string
+	pokemon Pokemon
+	// caught is nil when the subject is a species
+	caught    *CaughtPokemon
+	stats     map[string]int
+	abilities []string
+	moves     []string
+}
+
+// newCompareSubject looks for a caught pokemon first and falls back to the
+// species from the API, so "compare 3 garchomp" compares your #3 against a
+// garchomp.
+func newCompareSubject(conf *config, param string) (compareSubject, error) {
+	if caught, err := conf.pokedex.Find(param); err == nil {
+		pokemon, err := conf.api.getPokemon(caught.Species)
+		if err != nil {
+			return compareSubject{}, err
+		}
+		return compareSubject{
+			label:   fmt.Sprintf("#%d %s", caught.ID, caught.Name()),
+			pokemon: pokemon,
+			caught:  caught,
+		}, nil
+	}
+
+	pokemon, err := conf.api.getPokemonForSpecies(param)
+	if err != nil {
+		return compareSubject{}, err
+	}
+	return compareSubject{label: pokemon.Name, pokemon: pokemon}, nil
+}
+
+// fillIn sets the stats, abilities and moves to compare. Caught pokemon are
+// compared by their actual stats, ability and known moves unless base is
+// set, which compares the base stats, abilities and learnset of the species
+// instead.
+func (s *compareSubject) fillIn(base bool) {
+	if s.caught != nil && !base {
+		s.stats = calcStats(s.pokemon, s.caught)
+		s.abilities = []string{}
+		if len(s.caught.Ability) > 0 {
+			s.abilities = []string{s.caught.Ability}
+		}
+		s.moves = s.caught.Moves
+		return
+	}
+
+	s.stats = map[string]int{}
+	for _, stat := range s.pokemon.Stats {
+		s.stats[stat.Stat.Name] = stat.BaseStat
+	}
+	s.abilities = []string{}
+	for _, ability := range s.pokemon.Abilities {
+		s.abilities = append(s.abilities, ability.Ability.Name)
+	}
+	s.moves = []string{}
+	for _, move := range s.pokemon.Moves {
+		s.moves = append(s.moves, move.Move.Name)
+	}
+}
+
+// statCells formats one row of numbers. Every column after the first shows
+// its difference from the first, and the highest value is marked with a *.
+func statCells(values []int) []string {
+	best := values[0]
+	for _, value := range values {
+		best = max(best, value)
+	}
+
+	cells := []string{}
+	for i, value := range values {
+		cell := strconv.Itoa(value)
+		if i > 0 && value != values[0] {
+			cell += fmt.Sprintf(" (%+d)", value-values[0])
+		}
+		if value == best {
+			cell += " *"
+		}
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// splitMoves finds the moves every moveset has and the ones only a single
+// moveset has.
+func splitMoves(movesets [][]string) (shared []string, unique [][]string) {
+	counts := map[string]int{}
+	for _, moves := range movesets {
+		seen := map[string]bool{}
+		for _, move := range moves {
+			if !seen[move] {
+				seen[move] = true
+				counts[move]++
+			}
+		}
+	}
+
+	shared = []string{}
+	for move, count := range counts {
+		if count == len(movesets) {
+			shared = append(shared, move)
+		}
+	}
+	sort.Strings(shared)
+
+	for _, moves := range movesets {
+		only := []string{}
+		for _, move := range moves {
+			if counts[move] == 1 && !containsString(only, move) {
+				only = append(only, move)
+			}
+		}
+		sort.Strings(only)
+		unique = append(unique, only)
+	}
+	return shared, unique
+}
+
+func printTable(out io.Writer, rows [][]string) {
+	widths := []int{}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	for _, row := range rows {
+		line := ""
+		for i, cell := range row {
+			line += fmt.Sprintf("%-*s  ", widths[i], cell)
+		}
+		fmt.Fprintln(out, strings.TrimRight(line, " "))
+	}
+}
+
+func commandCompare(conf *config, args []string) error {
+	flags, positional, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return errors.New("Must pass at least two pokemon to the compare command")
+	}
+
+	subjects := []compareSubject{}
+	for _, arg := range positional {
+		subject, err := newCompareSubject(conf, arg)
+		if err != nil {
+			return err
+		}
+		subjects = append(subjects, subject)
+	}
+
+	// Every column is compared on one basis. A species has no actual stats,
+	// so comparing against one uses base stats for all of them.
+	base := flags["base"] == "true"
+	for _, subject := range subjects {
+		if subject.caught == nil {
+			base = true
+		}
+	}
+	basis, moveKind := "actual stats", "known moves"
+	if base {
+		basis, moveKind = "base stats", "learnable moves"
+	}
+	for i := range subjects {
+		subjects[i].fillIn(base)
+	}
+
+	header := []string{basis}
+	for _, subject := range subjects {
+		header = append(header, subject.label)
+	}
+	rows := [][]string{header}
+
+	addRow := func(label string, value func(compareSubject) string) {
+		row := []string{label}
+		for _, subject := range subjects {
+			row = append(row, value(subject))
+		}
+		rows = append(rows, row)
+	}
+	addStatRow := func(label string, value func(compareSubject) int) {
+		values := []int{}
+		for _, subject := range subjects {
+			values = append(values, value(subject))
+		}
+		rows = append(rows, append([]string{label}, statCells(values)...))
+	}
+
+	addRow("types", func(s compareSubject) string {
+		types := []string{}
+		for _, poketype := range s.pokemon.Types {
+			types = append(types, poketype.Type.Name)
+		}
+		return strings.Join(types, "/")
+	})
+	addRow("abilities", func(s compareSubject) string { return strings.Join(s.abilities, ", ") })
+	addRow("height", func(s compareSubject) string { return strconv.Itoa(s.pokemon.Height) })
+	addRow("weight", func(s compareSubject) string { return strconv.Itoa(s.pokemon.Weight) })
+	for _, stat := range statNames {
+		addStatRow(stat, func(s compareSubject) int { return s.stats[stat] })
+	}
+	addStatRow("total", func(s compareSubject) int {
+		total := 0
+		for _, value := range s.stats {
+			total += value
+		}
+		return total
+	})
+	printTable(conf.out, rows)
+
+	movesets := [][]string{}
+	for _, subject := range subjects {
+		movesets = append(movesets, subject.moves)
+	}
+	shared, unique := splitMoves(movesets)
+	fmt.Fprintf(conf.out, "Shared %s: %s\n", moveKind, listOrNone(shared))
+	for i, subject := range subjects {
+		fmt.Fprintf(conf.out, "Only %s: %s\n", subject.label, listOrNone(unique[i]))
+	}
+
+	return nil
+}
+
+func listOrNone(list []string) string {
+	if len(list) == 0 {
+		return "none"
+	}
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStatCells(t *testing.T) {
+	cases := []struct {
+		values   []int
+		expected []string
+	}{
+		{values: []int{100, 80}, expected: []string{"100 *", "80 (-20)"}},
+		{values: []int{60, 95, 95}, expected: []string{"60", "95 (+35) *", "95 (+35) *"}},
+		{values: []int{50, 50}, expected: []string{"50 *", "50 *"}},
+	}
+
+	for _, c := range cases {
+		actual := statCells(c.values)
+		if strings.Join(actual, "|") != strings.Join(c.expected, "|") {
+			t.Errorf("%v: actual %q != expected %q", c.values, actual, c.expected)
+		}
+	}
+}
+
+func TestSplitMoves(t *testing.T) {
+	shared, unique := splitMoves([][]string{
+		{"tackle", "surf", "bite"},
+		{"surf", "tackle", "thunderbolt"},
+		{"tackle", "surf", "bite", "growl"},
+	})
+
+	if strings.Join(shared, ",") != "surf,tackle" {
+		t.Errorf("shared: actual %v", shared)
+	}
+	expected := []string{"", "thunderbolt", "growl"}
+	for i := range expected {
+		if strings.Join(unique[i], ",") != expected[i] {
+			t.Errorf("unique %d: actual %v != expected %s", i, unique[i], expected[i])
+		}
+	}
+}
+
+func TestCompareSubjectBasis(t *testing.T) {
+	var pokemon Pokemon
+	err := json.Unmarshal([]byte(`{
+		"name": "pikachu",
+		"stats": [{"base_stat": 35, "stat": {"name": "hp"}}, {"base_stat": 90, "stat": {"name": "speed"}}],
+		"abilities": [{"ability": {"name": "static"}}, {"ability": {"name": "lightning-rod"}}],
+		"moves": [{"move": {"name": "thunder-shock"}}, {"move": {"name": "surf"}}]
+	}`), &pokemon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	caught := &CaughtPokemon{Species: "pikachu", Level: 50, Ability: "static", Moves: []string{"thunder-shock"}}
+
+	subject := compareSubject{pokemon: pokemon, caught: caught}
+	subject.fillIn(false)
+	if subject.stats["hp"] == 35 || strings.Join(subject.abilities, ",") != "static" || strings.Join(subject.moves, ",") != "thunder-shock" {
+		t.Errorf("actual basis: unexpected %v %v %v", subject.stats, subject.abilities, subject.moves)
+	}
+
+	subject.fillIn(true)
+	if subject.stats["hp"] != 35 || subject.stats["speed"] != 90 {
+		t.Errorf("base basis: unexpected stats %v", subject.stats)
+	}
+	if strings.Join(subject.abilities, ",") != "static,lightning-rod" || strings.Join(subject.moves, ",") != "thunder-shock,surf" {
+		t.Errorf("base basis: unexpected %v %v", subject.abilities, subject.moves)
+	}
+}
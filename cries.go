@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mikeheiberger/pokedexcli/internal/pokedexapi"
+)
+
+// cry fetches a pokemon's cry through the asset cache. The legacy cry is the
+// sound from the older games, which not every pokemon has.
+func (c *apiClient) cry(pokemon Pokemon, legacy bool) (pokedexapi.Asset, error) {
+	url := pokemon.Cries.Latest
+	if legacy {
+		url = pokemon.Cries.Legacy
+	}
+	if len(url) == 0 {
+		return pokedexapi.Asset{}, fmt.Errorf("%s has no cry recorded", pokemon.Name)
+	}
+	return c.assets.Get(url)
+}
+
+// cryDataUri embeds a pokemon's latest cry in a data URI, like
+// spriteDataUri does for sprites.
+func (c *apiClient) cryDataUri(pokemon Pokemon) (string, error) {
+	asset, err := c.cry(pokemon, false)
+	if err != nil {
+		return "", err
+	}
+	return assetDataUri(asset), nil
+}
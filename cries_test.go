@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCry(t *testing.T) {
+	requested := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Write([]byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00\x01vorbis"))
+	}))
+	defer server.Close()
+
+	conf, _ := newTestConfig(t, nil)
+	var pokemon Pokemon
+	pokemon.Name = "pikachu"
+	pokemon.Cries.Latest = server.URL + "/latest/25.ogg"
+	pokemon.Cries.Legacy = server.URL + "/legacy/25.ogg"
+
+	asset, err := conf.api.cry(pokemon, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset.ContentType != "application/ogg" {
+		t.Errorf("content type: actual %s != expected application/ogg", asset.ContentType)
+	}
+
+	uri, err := conf.api.cryDataUri(pokemon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(uri, "data:application/ogg;base64,") {
+		t.Errorf("unexpected data URI %s", uri)
+	}
+
+	// A second fetch comes from the asset cache
+	conf.api.cry(pokemon, false)
+	expected := "/legacy/25.ogg,/latest/25.ogg"
+	if strings.Join(requested, ",") != expected {
+		t.Errorf("actual requests %v != expected %s", requested, expected)
+	}
+
+	pokemon.Cries.Legacy = ""
+	if _, err := conf.api.cry(pokemon, true); err == nil {
+		t.Errorf("expected an error for a missing cry")
+	}
+}
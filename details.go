@@ -0,0 +1,38 @@
+package main
+
+// pokemonDetails is what inspect shows for a caught pokemon, combining the
+// individual with its species. Export writes the same details to files.
+type pokemonDetails struct {
+	caught  *CaughtPokemon
+	pokemon Pokemon
+	stats   map[string]int
+	types   []string
+	// sprite and cry are data URIs, only filled in for HTML exports
+	sprite string
+	cry    string
+}
+
+func (c *apiClient) newPokemonDetails(caught *CaughtPokemon) (pokemonDetails, error) {
+	pokemon, err := c.getPokemon(caught.Species)
+	if err != nil {
+		return pokemonDetails{}, err
+	}
+
+	details := pokemonDetails{
+		caught:  caught,
+		pokemon: pokemon,
+		stats:   calcStats(pokemon, caught),
+	}
+	for _, poketype := range pokemon.Types {
+		details.types = append(details.types, poketype.Type.Name)
+	}
+	return details, nil
+}
+
+// spriteOptions matches the sprite to the individual, so shiny and female
+// pokemon are drawn the way they look.
+func (d pokemonDetails) spriteOptions(opts spriteOptions) spriteOptions {
+	opts.shiny = opts.shiny || d.caught.Shiny
+	opts.female = d.caught.Gender == "female"
+	return opts
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PokedexResponse struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	PokemonEntries []struct {
+		EntryNumber    int           `json:"entry_number"`
+		PokemonSpecies namedResource `json:"pokemon_species"`
+	} `json:"pokemon_entries"`
+	Region *namedResource `json:"region"`
+}
+
+type RegionResponse struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	Pokedexes      []namedResource `json:"pokedexes"`
+	Locations      []namedResource `json:"locations"`
+	MainGeneration *namedResource  `json:"main_generation"`
+}
+
+type GenerationResponse struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	PokemonSpecies []namedResource `json:"pokemon_species"`
+	MainRegion     namedResource   `json:"main_region"`
+}
+
+// dexEntry is one species in a pokedex listing.
+type dexEntry struct {
+	number  int
+	species string
+}
+
+var romanNumerals = []string{"i", "ii", "iii", "iv", "v", "vi", "vii", "viii", "ix", "x"}
+
+// MarkSeen records that the trainer has come across a species. Encounters
+// only give pokemon names, so look those up with speciesOf first.
+func (p *Pokedex) MarkSeen(species string) {
+	p.Seen[species] = true
+}
+
+// MarkCaught records the first time a species was caught. Releasing the
+// pokemon later doesn't take it out of the pokedex.
+func (p *Pokedex) MarkCaught(species string, when time.Time) {
+	p.Seen[species] = true
+	if _, ok := p.SpeciesCaught[species]; !ok {
+		p.SpeciesCaught[species] = when
+	}
+}
+
+func (p *Pokedex) Status(species string) string {
+	if _, ok := p.SpeciesCaught[species]; ok {
+		return "caught"
+	}
+	if p.Seen[species] {
+		return "seen"
+	}
+	return ""
+}
+
+func (p *Pokedex) countOwned(species string) int {
+	owned := 0
+	for _, caught := range p.Caught {
+		if caught.dexSpecies() == species {
+			owned++
+		}
+	}
+	return owned
+}
+
+func (c *apiClient) getJsonResource(url string, target any) error {
+	jsonData, err := c.getJsonFromCacheOrServer(url)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(jsonData, target)
+	if err != nil {
+		return fmt.Errorf("Unmarshal failed: %v", err)
+	}
+	return nil
+}
+
+func (c *apiClient) getPokedexList(name string) (PokedexResponse, error) {
+	var dex PokedexResponse
+	err := c.getJsonResource("https://pokeapi.co/api/v2/pokedex/"+name+"/", &dex)
+	return dex, err
+}
+
+func (c *apiClient) getRegion(name string) (RegionResponse, error) {
+	var region RegionResponse
+	err := c.getJsonResource("https://pokeapi.co/api/v2/region/"+name+"/", &region)
+	return region, err
+}
+
+func (c *apiClient) getGeneration(name string) (GenerationResponse, error) {
+	var generation GenerationResponse
+	err := c.getJsonResource("https://pokeapi.co/api/v2/generation/"+generationName(name)+"/", &generation)
+	return generation, err
+}
+
+// generationName accepts "1", "i" or "generation-i" for the first generation.
+func generationName(name string) string {
+	if num, err := strconv.Atoi(name); err == nil && num >= 1 && num <= len(romanNumerals) {
+		return "generation-" + romanNumerals[num-1]
+	}
+	for _, numeral := range romanNumerals {
+		if name == numeral {
+			return "generation-" + numeral
+		}
+	}
+	return name
+}
+
+func dexEntries(dex PokedexResponse) []dexEntry {
+	entries := []dexEntry{}
+	for _, entry := range dex.PokemonEntries {
+		entries = append(entries, dexEntry{entry.EntryNumber, entry.PokemonSpecies.Name})
+	}
+	return entries
+}
+
+// regionEntries lists a region's species numbered by its first pokedex, with
+// species only in its other pokedexes (like kalos' three) added at the end.
+func (c *apiClient) regionEntries(name string) ([]dexEntry, error) {
+	region, err := c.getRegion(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(region.Pokedexes) == 0 {
+		return nil, fmt.Errorf("%s has no pokedex", region.Name)
+	}
+
+	entries := []dexEntry{}
+	included := map[string]bool{}
+	for _, resource := range region.Pokedexes {
+		dex, err := c.getPokedexList(resource.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range dexEntries(dex) {
+			if included[entry.species] {
+				continue
+			}
+			included[entry.species] = true
+			if len(entries) > 0 && entry.number <= entries[len(entries)-1].number {
+				entry.number = entries[len(entries)-1].number + 1
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// generationEntries lists the species introduced in a generation using their
+// national numbers.
+func (c *apiClient) generationEntries(national []dexEntry, name string) ([]dexEntry, error) {
+	generation, err := c.getGeneration(name)
+	if err != nil {
+		return nil, err
+	}
+
+	inGeneration := map[string]bool{}
+	for _, species := range generation.PokemonSpecies {
+		inGeneration[species.Name] = true
+	}
+
+	entries := []dexEntry{}
+	for _, entry := range national {
+		if inGeneration[entry.species] {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func completion(conf *config, entries []dexEntry) (seen int, caught int) {
+	for _, entry := range entries {
+		switch conf.pokedex.Status(entry.species) {
+		case "caught":
+			caught++
+			seen++
+		case "seen":
+			seen++
+		}
+	}
+	return seen, caught
+}
+
+func percent(part int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+func printCompletion(conf *config, label string, entries []dexEntry) {
+	seen, caught := completion(conf, entries)
+	fmt.Fprintf(conf.out, "%s: seen %d, caught %d of %d (%.1f%%)\n", label, seen, caught, len(entries), percent(caught, len(entries)))
+}
+
+// printDexProgress shows completion for every generation and region.
+func printDexProgress(conf *config, national []dexEntry) error {
+	printCompletion(conf, "National", national)
+
+	var generations resourceList
+	err := conf.api.getJsonResource("https://pokeapi.co/api/v2/generation/?limit=100", &generations)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(conf.out, "By generation:")
+	for _, resource := range generations.Results {
+		entries, err := conf.api.generationEntries(national, resource.Name)
+		if err != nil {
+			return err
+		}
+		printCompletion(conf, "\t"+resource.Name, entries)
+	}
+
+	var regions resourceList
+	err = conf.api.getJsonResource("https://pokeapi.co/api/v2/region/?limit=100", &regions)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(conf.out, "By region:")
+	for _, resource := range regions.Results {
+		entries, err := conf.api.regionEntries(resource.Name)
+		if err != nil {
+			// Regions without a pokedex of their own are skipped
+			continue
+		}
+		printCompletion(conf, "\t"+resource.Name, entries)
+	}
+
+	return nil
+}
+
+// printDex lists the entries that match the status filter: "seen", "caught",
+// "missing" or "" for every entry that has at least been seen.
+func printDex(conf *config, entries []dexEntry, filter string) {
+	for _, entry := range entries {
+		status := conf.pokedex.Status(entry.species)
+		switch filter {
+		case "missing":
+			if status == "caught" {
+				continue
+			}
+		case "seen":
+			if status != "seen" {
+				continue
+			}
+		case "caught":
+			if status != "caught" {
+				continue
+			}
+		default:
+			if len(status) == 0 {
+				continue
+			}
+		}
+
+		line := fmt.Sprintf("#%04d %-14s", entry.number, entry.species)
+		switch status {
+		case "caught":
+			line += " caught"
+			if owned := conf.pokedex.countOwned(entry.species); owned > 0 {
+				line += fmt.Sprintf(" (%d owned)", owned)
+			}
+		case "seen":
+			line += " seen"
+		default:
+			line += " ---"
+		}
+		fmt.Fprintln(conf.out, strings.TrimRight(line, " "))
+	}
+}
+
+// dexScope picks the entries the --region or --generation flags ask for,
+// defaulting to the national pokedex.
+func (c *apiClient) dexScope(flags map[string]string) ([]dexEntry, string, error) {
+	dex, err := c.getPokedexList("national")
+	if err != nil {
+		return nil, "", err
+	}
+	national := dexEntries(dex)
+
+	if region, ok := flags["region"]; ok {
+		entries, err := c.regionEntries(region)
+		return entries, region, err
+	}
+	if generation, ok := flags["generation"]; ok {
+		entries, err := c.generationEntries(national, generation)
+		return entries, generationName(generation), err
+	}
+	return national, "National", nil
+}
+
+// showDex prints the pokedex view for the pokedex command, scoped by the
+// --region or --generation flags and filtered by --missing, --seen or --caught.
+func showDex(conf *config, flags map[string]string) error {
+	if flags["progress"] == "true" {
+		dex, err := conf.api.getPokedexList("national")
+		if err != nil {
+			return err
+		}
+		return printDexProgress(conf, dexEntries(dex))
+	}
+
+	entries, label, err := conf.api.dexScope(flags)
+	if err != nil {
+		return err
+	}
+
+	filter := ""
+	for _, name := range []string{"missing", "seen", "caught"} {
+		if flags[name] == "true" {
+			filter = name
+		}
+	}
+
+	printDex(conf, entries, filter)
+	printCompletion(conf, label, entries)
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// dexResponses holds a small national pokedex, a kanto split across two
+// pokedexes the way kalos is, a region without a pokedex and the second
+// generation.
+var dexResponses = map[string]string{
+	"https://pokeapi.co/api/v2/pokedex/national/": `{"name": "national", "pokemon_entries": [
+		{"entry_number": 1, "pokemon_species": {"name": "bulbasaur"}},
+		{"entry_number": 25, "pokemon_species": {"name": "pikachu"}},
+		{"entry_number": 133, "pokemon_species": {"name": "eevee"}},
+		{"entry_number": 152, "pokemon_species": {"name": "chikorita"}}
+	]}`,
+	"https://pokeapi.co/api/v2/region/kanto/": `{"name": "kanto", "pokedexes": [{"name": "kanto"}, {"name": "kanto-extra"}]}`,
+	"https://pokeapi.co/api/v2/pokedex/kanto/": `{"name": "kanto", "pokemon_entries": [
+		{"entry_number": 1, "pokemon_species": {"name": "bulbasaur"}},
+		{"entry_number": 25, "pokemon_species": {"name": "pikachu"}},
+		{"entry_number": 133, "pokemon_species": {"name": "eevee"}}
+	]}`,
+	"https://pokeapi.co/api/v2/pokedex/kanto-extra/": `{"name": "kanto-extra", "pokemon_entries": [
+		{"entry_number": 1, "pokemon_species": {"name": "pikachu"}},
+		{"entry_number": 2, "pokemon_species": {"name": "chikorita"}}
+	]}`,
+	"https://pokeapi.co/api/v2/region/orre/":              `{"name": "orre", "pokedexes": []}`,
+	"https://pokeapi.co/api/v2/generation/generation-ii/": `{"name": "generation-ii", "pokemon_species": [{"name": "chikorita"}]}`,
+}
+
+// newDexConfig is a trainer who has caught a pikachu and seen an eevee.
+func newDexConfig(t *testing.T) (*config, *bytes.Buffer) {
+	conf, out := newTestConfig(t, dexResponses)
+	conf.pokedex.Add(&CaughtPokemon{Species: "pikachu"})
+	conf.pokedex.MarkCaught("pikachu", time.Now())
+	conf.pokedex.MarkSeen("eevee")
+	return conf, out
+}
+
+func TestRegionEntries(t *testing.T) {
+	conf, _ := newDexConfig(t)
+
+	entries, err := conf.api.regionEntries("kanto")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Species only in the second pokedex carry on from the end of the first
+	expected := []dexEntry{{1, "bulbasaur"}, {25, "pikachu"}, {133, "eevee"}, {134, "chikorita"}}
+	if len(entries) != len(expected) {
+		t.Fatalf("actual %v != expected %v", entries, expected)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("actual %v != expected %v", entries, expected)
+			break
+		}
+	}
+
+	if _, err := conf.api.regionEntries("orre"); err == nil {
+		t.Errorf("expected an error for a region without a pokedex")
+	}
+}
+
+func TestGenerationEntries(t *testing.T) {
+	conf, _ := newDexConfig(t)
+	national, _, err := conf.api.dexScope(map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"2", "ii", "generation-ii"} {
+		entries, err := conf.api.generationEntries(national, name)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if len(entries) != 1 || entries[0] != (dexEntry{152, "chikorita"}) {
+			t.Errorf("%s: actual %v != expected chikorita #152", name, entries)
+		}
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	conf, _ := newDexConfig(t)
+	entries := []dexEntry{{1, "bulbasaur"}, {25, "pikachu"}, {133, "eevee"}, {152, "chikorita"}}
+
+	seen, caught := completion(conf, entries)
+	if seen != 2 || caught != 1 {
+		t.Errorf("actual seen %d caught %d != expected seen 2 caught 1", seen, caught)
+	}
+
+	cases := []struct {
+		part     int
+		total    int
+		expected float64
+	}{
+		{1, 4, 25},
+		{0, 4, 0},
+		{3, 3, 100},
+		{0, 0, 0},
+	}
+	for _, c := range cases {
+		actual := percent(c.part, c.total)
+		if actual != c.expected {
+			t.Errorf("percent(%d, %d): actual %v != expected %v", c.part, c.total, actual, c.expected)
+		}
+	}
+}
+
+func TestShowDex(t *testing.T) {
+	cases := []struct {
+		flags    map[string]string
+		expected string
+	}{
+		{
+			map[string]string{},
+			"#0025 pikachu        caught (1 owned)\n#0133 eevee          seen\nNational: seen 2, caught 1 of 4 (25.0%)\n",
+		},
+		{
+			map[string]string{"region": "kanto", "missing": "true"},
+			"#0001 bulbasaur      ---\n#0133 eevee          seen\n#0134 chikorita      ---\nkanto: seen 2, caught 1 of 4 (25.0%)\n",
+		},
+		{
+			map[string]string{"region": "kanto", "seen": "true"},
+			"#0133 eevee          seen\nkanto: seen 2, caught 1 of 4 (25.0%)\n",
+		},
+		{
+			map[string]string{"generation": "2"},
+			"generation-ii: seen 0, caught 0 of 1 (0.0%)\n",
+		},
+		{
+			map[string]string{"generation": "2", "missing": "true"},
+			"#0152 chikorita      ---\ngeneration-ii: seen 0, caught 0 of 1 (0.0%)\n",
+		},
+	}
+
+	for _, c := range cases {
+		conf, out := newDexConfig(t)
+		err := showDex(conf, c.flags)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.flags, err)
+			continue
+		}
+		if out.String() != c.expected {
+			t.Errorf("%v: actual %q != expected %q", c.flags, out.String(), c.expected)
+		}
+	}
+}
@@ -0,0 +1,535 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type EvolutionDetail struct {
+	Trigger               namedResource  `json:"trigger"`
+	MinLevel              int            `json:"min_level"`
+	MinHappiness          int            `json:"min_happiness"`
+	MinAffection          int            `json:"min_affection"`
+	MinBeauty             int            `json:"min_beauty"`
+	TimeOfDay             string         `json:"time_of_day"`
+	Gender                int            `json:"gender"`
+	Item                  *namedResource `json:"item"`
+	HeldItem              *namedResource `json:"held_item"`
+	KnownMove             *namedResource `json:"known_move"`
+	KnownMoveType         *namedResource `json:"known_move_type"`
+	Location              *namedResource `json:"location"`
+	PartySpecies          *namedResource `json:"party_species"`
+	PartyType             *namedResource `json:"party_type"`
+	TradeSpecies          *namedResource `json:"trade_species"`
+	RelativePhysicalStats *int           `json:"relative_physical_stats"`
+	NeedsOverworldRain    bool           `json:"needs_overworld_rain"`
+	TurnUpsideDown        bool           `json:"turn_upside_down"`
+}
+
+type ChainLink struct {
+	Species          namedResource     `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+type EvolutionChainResponse struct {
+	ID    int       `json:"id"`
+	Chain ChainLink `json:"chain"`
+}
+
+// evolutionRequest is everything about the trainer's situation that can
+// decide whether a pokemon evolves.
+type evolutionRequest struct {
+	item      string
+	traded    bool
+	tradedFor string
+	when      time.Time
+	area      string
+}
+
+func (c *apiClient) getEvolutionChain(species PokemonSpecies) (EvolutionChainResponse, error) {
+	var chain EvolutionChainResponse
+	if len(species.EvolutionChain.URL) == 0 {
+		return chain, fmt.Errorf("%s has no evolution chain", species.Name)
+	}
+
+	jsonData, err := c.getJsonFromCacheOrServer(species.EvolutionChain.URL)
+	if err != nil {
+		return chain, err
+	}
+
+	err = json.Unmarshal(jsonData, &chain)
+	if err != nil {
+		return chain, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+
+	return chain, nil
+}
+
+// find returns the link for a species somewhere in the chain.
+func (l *ChainLink) find(species string) *ChainLink {
+	if l.Species.Name == species {
+		return l
+	}
+	for i := range l.EvolvesTo {
+		if found := l.EvolvesTo[i].find(species); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// describe turns evolution conditions into text like "level 16" or
+// "use water-stone, during the day".
+func (d EvolutionDetail) describe() string {
+	parts := []string{}
+	switch d.Trigger.Name {
+	case "level-up":
+		if d.MinLevel > 0 {
+			parts = append(parts, fmt.Sprintf("level %d", d.MinLevel))
+		} else {
+			parts = append(parts, "level up")
+		}
+	case "use-item":
+		if d.Item != nil {
+			parts = append(parts, "use "+d.Item.Name)
+		}
+	case "trade":
+		parts = append(parts, "trade")
+	default:
+		parts = append(parts, d.Trigger.Name)
+	}
+
+	if d.MinHappiness > 0 {
+		parts = append(parts, fmt.Sprintf("friendship %d", d.MinHappiness))
+	}
+	if d.MinAffection > 0 {
+		parts = append(parts, fmt.Sprintf("affection %d", d.MinAffection))
+	}
+	if d.MinBeauty > 0 {
+		parts = append(parts, fmt.Sprintf("beauty %d", d.MinBeauty))
+	}
+	if len(d.TimeOfDay) > 0 {
+		parts = append(parts, "during the "+d.TimeOfDay)
+	}
+	if d.Gender == 1 {
+		parts = append(parts, "female")
+	} else if d.Gender == 2 {
+		parts = append(parts, "male")
+	}
+	if d.HeldItem != nil {
+		parts = append(parts, "holding "+d.HeldItem.Name)
+	}
+	if d.KnownMove != nil {
+		parts = append(parts, "knowing "+d.KnownMove.Name)
+	}
+	if d.KnownMoveType != nil {
+		parts = append(parts, "knowing a "+d.KnownMoveType.Name+" move")
+	}
+	if d.Location != nil {
+		parts = append(parts, "at "+d.Location.Name)
+	}
+	if d.PartySpecies != nil {
+		parts = append(parts, "with "+d.PartySpecies.Name+" in the party")
+	}
+	if d.PartyType != nil {
+		parts = append(parts, "with a "+d.PartyType.Name+" pokemon in the party")
+	}
+	if d.TradeSpecies != nil {
+		parts = append(parts, "for "+d.TradeSpecies.Name)
+	}
+	if d.RelativePhysicalStats != nil {
+		switch *d.RelativePhysicalStats {
+		case 1:
+			parts = append(parts, "attack > defense")
+		case -1:
+			parts = append(parts, "attack < defense")
+		default:
+			parts = append(parts, "attack = defense")
+		}
+	}
+	if d.NeedsOverworldRain {
+		parts = append(parts, "while raining")
+	}
+	if d.TurnUpsideDown {
+		parts = append(parts, "holding the console upside down")
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func describeEvolution(details []EvolutionDetail) string {
+	descriptions := []string{}
+	for _, detail := range details {
+		descriptions = append(descriptions, detail.describe())
+	}
+	return strings.Join(descriptions, " or ")
+}
+
+// renderChain draws the chain as a tree, marking the highlighted species.
+func renderChain(link ChainLink, highlight string) []string {
+	name := link.Species.Name
+	if name == highlight {
+		name += " *"
+	}
+	lines := []string{name}
+	return append(lines, renderBranches(link, highlight, "")...)
+}
+
+func renderBranches(link ChainLink, highlight string, prefix string) []string {
+	lines := []string{}
+	for i, next := range link.EvolvesTo {
+		branch, indent := "├─ ", "│  "
+		if i == len(link.EvolvesTo)-1 {
+			branch, indent = "└─ ", "   "
+		}
+
+		name := next.Species.Name
+		if name == highlight {
+			name += " *"
+		}
+		lines = append(lines, fmt.Sprintf("%s%s%s (%s)", prefix, branch, name, describeEvolution(next.EvolutionDetails)))
+		lines = append(lines, renderBranches(next, highlight, prefix+indent)...)
+	}
+	return lines
+}
+
+func timeOfDay(when time.Time) string {
+	hour := when.Hour()
+	if hour >= 4 && hour < 10 {
+		return "morning"
+	}
+	if hour >= 10 && hour < 17 {
+		return "day"
+	}
+	if hour >= 17 && hour < 20 {
+		return "dusk"
+	}
+	return "night"
+}
+
+// evolutionBlockers lists the conditions of an evolution that the pokemon
+// doesn't meet. An empty list means it can evolve.
+func evolutionBlockers(conf *config, detail EvolutionDetail, caught *CaughtPokemon, pokemon Pokemon, req evolutionRequest) []string {
+	blockers := []string{}
+
+	switch detail.Trigger.Name {
+	case "level-up":
+		if detail.MinLevel > caught.Level {
+			blockers = append(blockers, fmt.Sprintf("needs to reach level %d", detail.MinLevel))
+		}
+	case "use-item":
+		if detail.Item != nil && req.item != detail.Item.Name {
+			blockers = append(blockers, "needs a "+detail.Item.Name)
+		}
+	case "trade":
+		if !req.traded {
+			blockers = append(blockers, "evolves when traded")
+		}
+	default:
+		blockers = append(blockers, "evolves by "+detail.Trigger.Name+", which isn't possible in the pokedex")
+	}
+
+	if detail.MinHappiness > caught.Friendship {
+		blockers = append(blockers, fmt.Sprintf("needs friendship %d (has %d)", detail.MinHappiness, caught.Friendship))
+	}
+	if detail.MinAffection > caught.Friendship {
+		blockers = append(blockers, fmt.Sprintf("needs affection %d (has %d)", detail.MinAffection, caught.Friendship))
+	}
+	if detail.MinBeauty > 0 {
+		blockers = append(blockers, "needs beauty, which isn't tracked by the pokedex")
+	}
+	if len(detail.TimeOfDay) > 0 {
+		now := timeOfDay(req.when)
+		// Morning and dusk are both part of the day
+		if now != detail.TimeOfDay && (detail.TimeOfDay != "day" || now == "night") {
+			blockers = append(blockers, "only evolves during the "+detail.TimeOfDay)
+		}
+	}
+	if detail.Gender == 1 && caught.Gender != "female" {
+		blockers = append(blockers, "only females evolve this way")
+	}
+	if detail.Gender == 2 && caught.Gender != "male" {
+		blockers = append(blockers, "only males evolve this way")
+	}
+	if detail.HeldItem != nil && req.item != detail.HeldItem.Name {
+		blockers = append(blockers, "needs to hold a "+detail.HeldItem.Name)
+	}
+	if detail.KnownMove != nil && !containsString(caught.Moves, detail.KnownMove.Name) {
+		blockers = append(blockers, "needs to know "+detail.KnownMove.Name)
+	}
+	if detail.KnownMoveType != nil && !conf.api.knowsMoveOfType(caught, detail.KnownMoveType.Name) {
+		blockers = append(blockers, "needs to know a "+detail.KnownMoveType.Name+" move")
+	}
+	if detail.Location != nil && !conf.api.inLocation(req.area, detail.Location.Name) {
+		blockers = append(blockers, "needs to be at "+detail.Location.Name)
+	}
+	if detail.PartySpecies != nil && !partyHasSpecies(conf, detail.PartySpecies.Name) {
+		blockers = append(blockers, "needs a "+detail.PartySpecies.Name+" in the party")
+	}
+	if detail.PartyType != nil && !partyHasType(conf, detail.PartyType.Name) {
+		blockers = append(blockers, "needs a "+detail.PartyType.Name+" pokemon in the party")
+	}
+	if detail.TradeSpecies != nil && req.tradedFor != detail.TradeSpecies.Name {
+		blockers = append(blockers, "needs to be traded for a "+detail.TradeSpecies.Name)
+	}
+	if detail.RelativePhysicalStats != nil {
+		stats := calcStats(pokemon, caught)
+		relative := 0
+		if stats["attack"] > stats["defense"] {
+			relative = 1
+		} else if stats["attack"] < stats["defense"] {
+			relative = -1
+		}
+		if relative != *detail.RelativePhysicalStats {
+			blockers = append(blockers, "needs different attack and defense")
+		}
+	}
+	if detail.NeedsOverworldRain {
+		blockers = append(blockers, "needs rain, which the pokedex can't check")
+	}
+	if detail.TurnUpsideDown {
+		blockers = append(blockers, "needs the console upside down, which the pokedex can't check")
+	}
+
+	return blockers
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *apiClient) knowsMoveOfType(caught *CaughtPokemon, moveType string) bool {
+	for _, name := range caught.Moves {
+		move, err := c.getMove(name)
+		if err == nil && move.Type.Name == moveType {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *apiClient) inLocation(area string, location string) bool {
+	if len(area) == 0 {
+		return false
+	}
+	explore, err := c.getLocationArea(area)
+	return err == nil && explore.Location.Name == location
+}
+
+func partyHasSpecies(conf *config, species string) bool {
+	for _, id := range conf.storage.Party {
+		if conf.pokedex.Caught[id].dexSpecies() == species {
+			return true
+		}
+	}
+	return false
+}
+
+func partyHasType(conf *config, poketype string) bool {
+	for _, id := range conf.storage.Party {
+		pokemon, err := conf.api.getPokemon(conf.pokedex.Caught[id].Species)
+		if err != nil {
+			continue
+		}
+		for _, t := range pokemon.Types {
+			if t.Type.Name == poketype {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var errDoesntEvolve = errors.New("doesn't evolve")
+
+// evolutionOptions finds the species a caught pokemon can evolve into right
+// now, and why it can't evolve into the others. A trade only sets off
+// evolutions triggered by trading.
+func evolutionOptions(conf *config, caught *CaughtPokemon, req evolutionRequest) ([]string, []string, error) {
+	pokemon, err := conf.api.getPokemon(caught.Species)
+	if err != nil {
+		return nil, nil, err
+	}
+	species, err := conf.api.getSpecies(pokemon.Species.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	chain, err := conf.api.getEvolutionChain(species)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	link := chain.Chain.find(species.Name)
+	if link == nil || len(link.EvolvesTo) == 0 {
+		return nil, nil, fmt.Errorf("%s %w", species.Name, errDoesntEvolve)
+	}
+
+	ready := []string{}
+	reasons := []string{}
+	for _, next := range link.EvolvesTo {
+		met := false
+		for _, detail := range next.EvolutionDetails {
+			if req.traded && detail.Trigger.Name != "trade" {
+				continue
+			}
+			blockers := evolutionBlockers(conf, detail, caught, pokemon, req)
+			if len(blockers) == 0 {
+				met = true
+				break
+			}
+			reasons = append(reasons, fmt.Sprintf("%s: %s", next.Species.Name, strings.Join(blockers, ", ")))
+		}
+		if met {
+			ready = append(ready, next.Species.Name)
+		}
+	}
+
+	return ready, reasons, nil
+}
+
+// evolvePokemon turns a caught pokemon into another species, keeping its
+// nickname, IVs, EVs and nature. The ability stays in the same slot.
+func (c *apiClient) evolvePokemon(dex *Pokedex, caught *CaughtPokemon, speciesName string) (string, error) {
+	before, err := c.getPokemon(caught.Species)
+	if err != nil {
+		return "", err
+	}
+	species, err := c.getSpeciesByName(speciesName)
+	if err != nil {
+		return "", err
+	}
+	after, err := c.getPokemon(species.defaultVariety())
+	if err != nil {
+		return "", err
+	}
+
+	slot := 0
+	for _, ability := range before.Abilities {
+		if ability.Ability.Name == caught.Ability {
+			slot = ability.Slot
+		}
+	}
+	for _, ability := range after.Abilities {
+		if ability.Slot == slot {
+			caught.Ability = ability.Ability.Name
+		}
+	}
+
+	previous := caught.Species
+	caught.Species = after.Name
+	caught.SpeciesName = species.Name
+	dex.MarkCaught(species.Name, time.Now())
+	return previous, nil
+}
+
+func commandEvolution(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Must pass a pokemon to the evolution command")
+	}
+
+	name := args[0]
+	if caught, err := conf.pokedex.Find(name); err == nil {
+		name = caught.Species
+	}
+
+	pokemon, err := conf.api.getPokemon(name)
+	if err != nil {
+		return err
+	}
+	species, err := conf.api.getSpecies(pokemon.Species.URL)
+	if err != nil {
+		return err
+	}
+	chain, err := conf.api.getEvolutionChain(species)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range renderChain(chain.Chain, species.Name) {
+		fmt.Fprintln(conf.out, line)
+	}
+
+	return nil
+}
+
+func commandEvolve(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Usage: evolve <pokemon> [item] [into=<species>]")
+	}
+
+	caught, err := conf.pokedex.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	req := evolutionRequest{
+		when: time.Now(),
+		area: conf.currentArea,
+	}
+	into := ""
+	for _, arg := range args[1:] {
+		if target, ok := strings.CutPrefix(arg, "into="); ok {
+			into = target
+		} else {
+			req.item = arg
+		}
+	}
+
+	if len(req.item) > 0 && conf.inventory.Count(req.item) == 0 {
+		return fmt.Errorf("You don't have a %s", req.item)
+	}
+
+	ready, reasons, err := evolutionOptions(conf, caught, req)
+	if err != nil {
+		return err
+	}
+
+	if len(into) > 0 {
+		if !containsString(ready, into) {
+			return fmt.Errorf("%s can't evolve into %s right now:\n\t%s", caught.Name(), into, strings.Join(reasons, "\n\t"))
+		}
+		ready = []string{into}
+	}
+
+	if len(ready) == 0 {
+		return fmt.Errorf("%s can't evolve right now:\n\t%s", caught.Name(), strings.Join(reasons, "\n\t"))
+	}
+	if len(ready) > 1 {
+		return fmt.Errorf("%s can evolve into %s, pick one with into=<species>", caught.Name(), strings.Join(ready, " or "))
+	}
+
+	// The item is used up only if the evolution needed it
+	useItem := false
+	if len(req.item) > 0 {
+		withoutItem := req
+		withoutItem.item = ""
+		readyWithout, _, err := evolutionOptions(conf, caught, withoutItem)
+		if err != nil {
+			return err
+		}
+		useItem = !containsString(readyWithout, ready[0])
+	}
+
+	name := caught.Name()
+	previous, err := conf.api.evolvePokemon(conf.pokedex, caught, ready[0])
+	if err != nil {
+		return err
+	}
+	if useItem {
+		conf.inventory.Use(req.item)
+	}
+
+	fmt.Fprintf(conf.out, "What? %s is evolving!\n", name)
+	fmt.Fprintf(conf.out, "Congratulations! Your %s evolved into %s!\n", previous, caught.Species)
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderChain(t *testing.T) {
+	chain := ChainLink{
+		Species: namedResource{Name: "eevee"},
+		EvolvesTo: []ChainLink{
+			{
+				Species: namedResource{Name: "vaporeon"},
+				EvolutionDetails: []EvolutionDetail{
+					{Trigger: namedResource{Name: "use-item"}, Item: &namedResource{Name: "water-stone"}},
+				},
+			},
+			{
+				Species: namedResource{Name: "umbreon"},
+				EvolutionDetails: []EvolutionDetail{
+					{Trigger: namedResource{Name: "level-up"}, MinHappiness: 160, TimeOfDay: "night"},
+				},
+			},
+		},
+	}
+
+	expected := []string{
+		"eevee",
+		"├─ vaporeon (use water-stone)",
+		"└─ umbreon * (level up, friendship 160, during the night)",
+	}
+	actual := renderChain(chain, "umbreon")
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("actual:\n%s\nexpected:\n%s", strings.Join(actual, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestEvolutionBlockers(t *testing.T) {
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	midnight := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		detail   EvolutionDetail
+		caught   CaughtPokemon
+		req      evolutionRequest
+		blockers int
+	}{
+		{
+			name:     "level reached",
+			detail:   EvolutionDetail{Trigger: namedResource{Name: "level-up"}, MinLevel: 16},
+			caught:   CaughtPokemon{Level: 16},
+			req:      evolutionRequest{when: noon},
+			blockers: 0,
+		},
+		{
+			name:     "level too low",
+			detail:   EvolutionDetail{Trigger: namedResource{Name: "level-up"}, MinLevel: 16},
+			caught:   CaughtPokemon{Level: 15},
+			req:      evolutionRequest{when: noon},
+			blockers: 1,
+		},
+		{
+			name:     "item given",
+			detail:   EvolutionDetail{Trigger: namedResource{Name: "use-item"}, Item: &namedResource{Name: "fire-stone"}},
+			caught:   CaughtPokemon{Level: 5},
+			req:      evolutionRequest{item: "fire-stone", when: noon},
+			blockers: 0,
+		},
+		{
+			name:     "wrong time and friendship",
+			detail:   EvolutionDetail{Trigger: namedResource{Name: "level-up"}, MinHappiness: 160, TimeOfDay: "night"},
+			caught:   CaughtPokemon{Level: 5, Friendship: 70},
+			req:      evolutionRequest{when: noon},
+			blockers: 2,
+		},
+		{
+			name:     "night with friendship",
+			detail:   EvolutionDetail{Trigger: namedResource{Name: "level-up"}, MinHappiness: 160, TimeOfDay: "night"},
+			caught:   CaughtPokemon{Level: 5, Friendship: 200},
+			req:      evolutionRequest{when: midnight},
+			blockers: 0,
+		},
+		{
+			name:     "not traded",
+			detail:   EvolutionDetail{Trigger: namedResource{Name: "trade"}},
+			caught:   CaughtPokemon{Level: 5},
+			req:      evolutionRequest{when: noon},
+			blockers: 1,
+		},
+		{
+			name:     "traded for the wrong species",
+			detail:   EvolutionDetail{Trigger: namedResource{Name: "trade"}, TradeSpecies: &namedResource{Name: "shelmet"}},
+			caught:   CaughtPokemon{Level: 5},
+			req:      evolutionRequest{traded: true, tradedFor: "pikachu", when: noon},
+			blockers: 1,
+		},
+	}
+
+	conf, _ := newTestConfig(t, nil)
+	for _, c := range cases {
+		actual := evolutionBlockers(conf, c.detail, &c.caught, Pokemon{}, c.req)
+		if len(actual) != c.blockers {
+			t.Errorf("%s: actual blockers %v, expected %d", c.name, actual, c.blockers)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type GrowthRateResponse struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Levels []struct {
+		Level      int `json:"level"`
+		Experience int `json:"experience"`
+	} `json:"levels"`
+}
+
+const maxLevel = 100
+
+const maxFriendship = 255
+
+func (c *apiClient) getGrowthRate(url string) (GrowthRateResponse, error) {
+	var rate GrowthRateResponse
+	jsonData, err := c.getJsonFromCacheOrServer(url)
+	if err != nil {
+		return rate, err
+	}
+
+	err = json.Unmarshal(jsonData, &rate)
+	if err != nil {
+		return rate, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+
+	return rate, nil
+}
+
+// experienceFor is the total experience needed to reach level.
+func (g GrowthRateResponse) experienceFor(level int) int {
+	for _, entry := range g.Levels {
+		if entry.Level == level {
+			return entry.Experience
+		}
+	}
+	return 0
+}
+
+// defeatExperience uses the generation I-IV formula: trainer pokemon give
+// half again as much and the experience is split between participants.
+func defeatExperience(baseExperience int, level int, trainer bool, participants int) int {
+	exp := baseExperience * level / 7
+	if trainer {
+		exp = exp * 3 / 2
+	}
+	return max(exp/max(participants, 1), 1)
+}
+
+// friendshipGain is how much friendship a pokemon gets from leveling up,
+// which is less the friendlier it already is.
+func friendshipGain(friendship int) int {
+	if friendship < 100 {
+		return 5
+	}
+	if friendship < 200 {
+		return 3
+	}
+	return 2
+}
+
+// gainEVs adds the effort values a defeated pokemon yields, up to the limits.
+func gainEVs(caught *CaughtPokemon, defeated Pokemon) {
+	total := 0
+	for _, ev := range caught.EVs {
+		total += ev
+	}
+
+	for _, stat := range defeated.Stats {
+		gain := min(stat.Effort, maxEV-caught.EVs[stat.Stat.Name], maxTotalEVs-total)
+		if gain > 0 {
+			caught.EVs[stat.Stat.Name] += gain
+			total += gain
+		}
+	}
+}
+
+// gainExperience adds experience to a caught pokemon, leveling it up and
+// teaching it new moves as it goes. It returns the number of levels gained.
+func gainExperience(conf *config, caught *CaughtPokemon, amount int) (int, error) {
+	pokemon, err := conf.api.getPokemon(caught.Species)
+	if err != nil {
+		return 0, err
+	}
+	species, err := conf.api.getSpecies(pokemon.Species.URL)
+	if err != nil {
+		return 0, err
+	}
+	rate, err := conf.api.getGrowthRate(species.GrowthRate.URL)
+	if err != nil {
+		return 0, err
+	}
+
+	// Pokemon from before experience was tracked start at their level's minimum
+	caught.Experience = max(caught.Experience, rate.experienceFor(caught.Level))
+	if caught.Level >= maxLevel {
+		return 0, nil
+	}
+
+	caught.Experience += amount
+	fmt.Fprintf(conf.out, "%s gained %d experience!\n", caught.Name(), amount)
+
+	levels := 0
+	for caught.Level < maxLevel && caught.Experience >= rate.experienceFor(caught.Level+1) {
+		caught.Level++
+		levels++
+		caught.Friendship = min(caught.Friendship+friendshipGain(caught.Friendship), maxFriendship)
+		fmt.Fprintf(conf.out, "%s grew to level %d!\n", caught.Name(), caught.Level)
+
+		for _, move := range movesLearnedAt(pokemon, caught.Level) {
+			offerMove(conf.out, caught, move)
+		}
+	}
+
+	return levels, nil
+}
+
+// offerMove teaches a pokemon a move if it has a free slot, otherwise the
+// move waits until the player picks one to forget with the learn command.
+func offerMove(out io.Writer, caught *CaughtPokemon, move string) {
+	if containsString(caught.Moves, move) || containsString(caught.PendingMoves, move) {
+		return
+	}
+
+	if len(caught.Moves) < maxMoves {
+		caught.Moves = append(caught.Moves, move)
+		fmt.Fprintf(out, "%s learned %s!\n", caught.Name(), move)
+		return
+	}
+
+	caught.PendingMoves = append(caught.PendingMoves, move)
+	fmt.Fprintf(out, "%s wants to learn %s, but already knows %d moves.\n", caught.Name(), move, maxMoves)
+	fmt.Fprintf(out, "Use learn %d %s <move to forget>, or learn %d %s skip\n", caught.ID, move, caught.ID, move)
+}
+
+// experienceToNext describes progress towards the next level for inspect.
+func (c *apiClient) experienceToNext(caught *CaughtPokemon, pokemon Pokemon) string {
+	species, err := c.getSpecies(pokemon.Species.URL)
+	if err != nil {
+		return fmt.Sprintf("%d", caught.Experience)
+	}
+	rate, err := c.getGrowthRate(species.GrowthRate.URL)
+	if err != nil {
+		return fmt.Sprintf("%d", caught.Experience)
+	}
+
+	exp := max(caught.Experience, rate.experienceFor(caught.Level))
+	if caught.Level >= maxLevel {
+		return fmt.Sprintf("%d (max level)", exp)
+	}
+	return fmt.Sprintf("%d (%d to next level)", exp, rate.experienceFor(caught.Level+1)-exp)
+}
+
+func commandLearn(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Usage: learn <pokemon> [<move> <move to forget>|skip]")
+	}
+
+	caught, err := conf.pokedex.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		if len(caught.PendingMoves) == 0 {
+			return fmt.Errorf("%s isn't trying to learn any moves", caught.Name())
+		}
+		fmt.Fprintf(conf.out, "%s knows: %s\n", caught.Name(), strings.Join(caught.Moves, ", "))
+		fmt.Fprintf(conf.out, "%s wants to learn: %s\n", caught.Name(), strings.Join(caught.PendingMoves, ", "))
+		return nil
+	}
+
+	if len(args) != 3 {
+		return errors.New("Usage: learn <pokemon> <move> <move to forget>|skip")
+	}
+
+	move, forget := args[1], args[2]
+	if !containsString(caught.PendingMoves, move) {
+		return fmt.Errorf("%s isn't trying to learn %s", caught.Name(), move)
+	}
+
+	if forget == "skip" {
+		caught.PendingMoves = removeString(caught.PendingMoves, move)
+		fmt.Fprintf(conf.out, "%s did not learn %s.\n", caught.Name(), move)
+		return nil
+	}
+
+	for i, known := range caught.Moves {
+		if known == forget {
+			caught.Moves[i] = move
+			caught.PendingMoves = removeString(caught.PendingMoves, move)
+			fmt.Fprintf(conf.out, "1, 2, and... Poof! %s forgot %s and learned %s!\n", caught.Name(), forget, move)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s doesn't know %s", caught.Name(), forget)
+}
+
+func removeString(list []string, value string) []string {
+	kept := []string{}
+	for _, item := range list {
+		if item != value {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
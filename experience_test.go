@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestDefeatExperience(t *testing.T) {
+	cases := []struct {
+		base         int
+		level        int
+		trainer      bool
+		participants int
+		expected     int
+	}{
+		{base: 64, level: 7, trainer: false, participants: 1, expected: 64},
+		{base: 64, level: 7, trainer: true, participants: 1, expected: 96},
+		{base: 64, level: 7, trainer: true, participants: 2, expected: 48},
+		{base: 1, level: 1, trainer: false, participants: 3, expected: 1},
+	}
+
+	for _, c := range cases {
+		actual := defeatExperience(c.base, c.level, c.trainer, c.participants)
+		if actual != c.expected {
+			t.Errorf("%+v: actual %v != expected %v", c, actual, c.expected)
+		}
+	}
+}
+
+func TestGainEVs(t *testing.T) {
+	defeated := Pokemon{}
+	defeated.Stats = make([]struct {
+		BaseStat int `json:"base_stat"`
+		Effort   int `json:"effort"`
+		Stat     struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"stat"`
+	}, 2)
+	defeated.Stats[0].Stat.Name = "speed"
+	defeated.Stats[0].Effort = 2
+	defeated.Stats[1].Stat.Name = "attack"
+	defeated.Stats[1].Effort = 1
+
+	caught := CaughtPokemon{EVs: map[string]int{"speed": 251, "hp": 252, "defense": 6}}
+	gainEVs(&caught, defeated)
+
+	if caught.EVs["speed"] != maxEV {
+		t.Errorf("speed EVs: actual %v != expected %v", caught.EVs["speed"], maxEV)
+	}
+	if caught.EVs["attack"] != 0 {
+		t.Errorf("attack EVs should be capped by the total, got %v", caught.EVs["attack"])
+	}
+}
+
+func TestStoreCaughtWithoutExperience(t *testing.T) {
+	// The lead's species can't be read, so it can't be given experience
+	conf, _ := newTestConfig(t, map[string]string{
+		"https://pokeapi.co/api/v2/pokemon/missingno/": "not json",
+	})
+	conf.storage.Store(conf.pokedex.Add(&CaughtPokemon{Species: "missingno", Level: 5}))
+
+	caught := &CaughtPokemon{Species: "pikachu", Level: 3}
+	conf.pokedex.Add(caught)
+	inParty, err := storeCaught(conf, Pokemon{BaseExperience: 112}, caught)
+	if err == nil {
+		t.Errorf("expected the experience error to be reported")
+	}
+	if !inParty || len(conf.storage.Party) != 2 || conf.storage.Party[1] != caught.ID {
+		t.Errorf("expected the catch to be stored anyway, party %v", conf.storage.Party)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// exportFormats maps the formats export understands to their writers.
+var exportFormats = map[string]func(io.Writer, []pokemonDetails) error{
+	"csv":      writeCSV,
+	"markdown": writeMarkdown,
+	"md":       writeMarkdown,
+	"html":     writeHTML,
+}
+
+// statHeaders are the short stat names used as column headers.
+var statHeaders = []string{"HP", "Atk", "Def", "SpA", "SpD", "Spe"}
+
+func shinyText(shiny bool) string {
+	if shiny {
+		return "yes"
+	}
+	return "no"
+}
+
+func writeCSV(w io.Writer, list []pokemonDetails) error {
+	writer := csv.NewWriter(w)
+	header := []string{"id", "nickname", "species", "level", "nature", "gender", "ability", "shiny", "types"}
+	header = append(header, statNames...)
+	header = append(header, "moves", "caught_at", "location")
+	writer.Write(header)
+
+	for _, details := range list {
+		caught := details.caught
+		row := []string{
+			strconv.Itoa(caught.ID),
+			caught.Nickname,
+			caught.Species,
+			strconv.Itoa(caught.Level),
+			caught.Nature,
+			caught.Gender,
+			caught.Ability,
+			shinyText(caught.Shiny),
+			strings.Join(details.types, "/"),
+		}
+		for _, stat := range statNames {
+			row = append(row, strconv.Itoa(details.stats[stat]))
+		}
+		row = append(row, strings.Join(caught.Moves, "/"), caught.CaughtAt.Format("2006-01-02 15:04"), caught.Location)
+		writer.Write(row)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func markdownRow(cells []string) string {
+	for i, cell := range cells {
+		cells[i] = strings.ReplaceAll(cell, "|", "\\|")
+	}
+	return "| " + strings.Join(cells, " | ") + " |\n"
+}
+
+func writeMarkdown(w io.Writer, list []pokemonDetails) error {
+	header := []string{"#", "Name", "Species", "Lv", "Types"}
+	header = append(header, statHeaders...)
+	header = append(header, "Nature", "Ability", "Shiny", "Caught")
+	divider := []string{}
+	for range header {
+		divider = append(divider, "---")
+	}
+
+	text := "# Pokedex\n\n" + markdownRow(header) + markdownRow(divider)
+	for _, details := range list {
+		caught := details.caught
+		row := []string{strconv.Itoa(caught.ID), caught.Name(), caught.Species, strconv.Itoa(caught.Level), strings.Join(details.types, "/")}
+		for _, stat := range statNames {
+			row = append(row, strconv.Itoa(details.stats[stat]))
+		}
+		row = append(row, caught.Nature, caught.Ability, shinyText(caught.Shiny), caught.CaughtAt.Format("2006-01-02"))
+		text += markdownRow(row)
+	}
+
+	_, err := io.WriteString(w, text)
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("pokedex").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Pokedex</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #eee; }
+img { image-rendering: pixelated; width: 64px; height: 64px; }
+</style>
+</head>
+<body>
+<h1>Pokedex</h1>
+<p>{{len .Rows}} pokemon</p>
+<table>
+<tr><th></th><th>#</th><th>Name</th><th>Species</th><th>Lv</th><th>Types</th>{{range .Stats}}<th>{{.}}</th>{{end}}<th>Nature</th><th>Ability</th><th>Moves</th><th>Caught</th></tr>
+{{range .Rows}}<tr><td>{{if .Sprite}}<img src="{{.Sprite}}" alt="{{.Species}}">{{end}}{{if .Cry}}<br><audio controls src="{{.Cry}}"></audio>{{end}}</td><td>{{.ID}}</td><td>{{.Name}}{{if .Shiny}} &#9733;{{end}}</td><td>{{.Species}}</td><td>{{.Level}}</td><td>{{.Types}}</td>{{range .Stats}}<td>{{.}}</td>{{end}}<td>{{.Nature}}</td><td>{{.Ability}}</td><td>{{.Moves}}</td><td>{{.Caught}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+type htmlRow struct {
+	Sprite  template.URL
+	Cry     template.URL
+	ID      int
+	Name    string
+	Species string
+	Shiny   bool
+	Level   int
+	Types   string
+	Stats   []int
+	Nature  string
+	Ability string
+	Moves   string
+	Caught  string
+}
+
+// writeHTML writes a single page with every sprite and cry embedded in it.
+func writeHTML(w io.Writer, list []pokemonDetails) error {
+	rows := []htmlRow{}
+	for _, details := range list {
+		caught := details.caught
+		row := htmlRow{
+			ID:      caught.ID,
+			Name:    caught.Name(),
+			Species: caught.Species,
+			Shiny:   caught.Shiny,
+			Level:   caught.Level,
+			Types:   strings.Join(details.types, "/"),
+			Nature:  caught.Nature,
+			Ability: caught.Ability,
+			Moves:   strings.Join(caught.Moves, ", "),
+			Caught:  caught.CaughtAt.Format("2006-01-02"),
+		}
+		for _, stat := range statNames {
+			row.Stats = append(row.Stats, details.stats[stat])
+		}
+		row.Sprite = template.URL(details.sprite)
+		row.Cry = template.URL(details.cry)
+		rows = append(rows, row)
+	}
+
+	return htmlTemplate.Execute(w, map[string]any{
+		"Stats": statHeaders,
+		"Rows":  rows,
+	})
+}
+
+func commandExport(conf *config, args []string) error {
+	if len(args) != 2 {
+		return errors.New("Usage: export csv|markdown|html <file>")
+	}
+
+	format := strings.ToLower(args[0])
+	write, ok := exportFormats[format]
+	if !ok {
+		return fmt.Errorf("Unknown export format %s, use csv, markdown or html", args[0])
+	}
+	if len(conf.pokedex.Caught) == 0 {
+		return errors.New("You haven't caught any Pokemon!")
+	}
+
+	list := []pokemonDetails{}
+	for _, caught := range conf.pokedex.Sorted() {
+		details, err := conf.api.newPokemonDetails(caught)
+		if err != nil {
+			return err
+		}
+		// Sprites and cries that can't be downloaded are left out rather
+		// than failing the export
+		if format == "html" {
+			details.sprite, _ = conf.api.spriteDataUri(details.pokemon, details.spriteOptions(spriteOptions{}))
+			details.cry, _ = conf.api.cryDataUri(details.pokemon)
+		}
+		list = append(list, details)
+	}
+
+	file, err := os.Create(args[1])
+	if err != nil {
+		return err
+	}
+
+	err = write(file, list)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	fmt.Fprintf(conf.out, "Exported %d pokemon to %s\n", len(list), args[1])
+	return nil
+}
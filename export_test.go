@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDetails() []pokemonDetails {
+	return []pokemonDetails{
+		{
+			caught: &CaughtPokemon{
+				ID:       1,
+				Species:  "pikachu",
+				Nickname: "sparky",
+				Level:    12,
+				Nature:   "timid",
+				Gender:   "male",
+				Ability:  "static",
+				Shiny:    true,
+				CaughtAt: time.Date(2024, 5, 4, 10, 30, 0, 0, time.UTC),
+				Location: "viridian-forest-area",
+				Moves:    []string{"thunder-shock", "growl"},
+			},
+			stats: map[string]int{"hp": 34, "attack": 18, "defense": 15, "special-attack": 18, "special-defense": 18, "speed": 31},
+			types: []string{"electric"},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeCSV(&buf, testDetails())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "id,nickname,species,level,nature,gender,ability,shiny,types,hp,attack,defense,special-attack,special-defense,speed,moves,caught_at,location\n" +
+		"1,sparky,pikachu,12,timid,male,static,yes,electric,34,18,15,18,18,31,thunder-shock/growl,2024-05-04 10:30,viridian-forest-area\n"
+	if buf.String() != expected {
+		t.Errorf("actual:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeMarkdown(&buf, testDetails())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "| 1 | sparky | pikachu | 12 | electric | 34 | 18 | 15 | 18 | 18 | 31 | timid | static | yes | 2024-05-04 |\n"
+	if !strings.HasSuffix(buf.String(), expected) {
+		t.Errorf("actual:\n%s\nexpected to end with:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	list := testDetails()
+	list[0].caught.Nickname = "<b>"
+	list[0].cry = "data:application/ogg;base64,T2dnUw=="
+
+	var buf bytes.Buffer
+	err := writeHTML(&buf, list)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"<td>&lt;b&gt; &#9733;</td>", "<td>pikachu</td>", "<td>31</td>", `<audio controls src="data:application/ogg;base64,T2dnUw=="></audio>`} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected the page to contain %s", expected)
+		}
+	}
+}
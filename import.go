@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// importPolicies decide what happens when both saves have the same pokemon,
+// which happens when a save is imported twice or two trainers share one.
+var importPolicies = []string{"keep-mine", "keep-theirs", "keep-both"}
+
+type importSummary struct {
+	added    int
+	replaced int
+	skipped  int
+	seen     []string
+	caught   []string
+}
+
+// sameIndividual reports whether two entries are the same pokemon. IDs are
+// only unique within one save, so it compares what was rolled at the catch.
+func sameIndividual(a *CaughtPokemon, b *CaughtPokemon) bool {
+	return a.Species == b.Species && a.CaughtAt.Equal(b.CaughtAt) && maps.Equal(a.IVs, b.IVs)
+}
+
+// mergePokedex copies their pokemon into dex, giving each a new ID and a
+// place in the PC. Nothing is changed when dryRun is set, the summary just
+// says what would happen.
+func mergePokedex(dex *Pokedex, store *Storage, theirs *Pokedex, policy string, dryRun bool) importSummary {
+	summary := importSummary{seen: []string{}, caught: []string{}}
+	mine := dex.Sorted()
+
+	for _, their := range theirs.Sorted() {
+		var match *CaughtPokemon
+		for _, my := range mine {
+			if sameIndividual(my, their) {
+				match = my
+				break
+			}
+		}
+
+		if match != nil && policy == "keep-mine" {
+			summary.skipped++
+			continue
+		}
+		if match != nil && policy == "keep-theirs" {
+			summary.replaced++
+			if !dryRun {
+				replacement := *their
+				replacement.ID = match.ID
+				dex.Caught[match.ID] = &replacement
+			}
+			continue
+		}
+
+		summary.added++
+		if !dryRun {
+			copied := *their
+			store.addToBox(dex.Add(&copied))
+		}
+	}
+
+	for species := range theirs.Seen {
+		if len(dex.Status(species)) == 0 {
+			summary.seen = append(summary.seen, species)
+		}
+	}
+	for species, when := range theirs.SpeciesCaught {
+		first, ok := dex.SpeciesCaught[species]
+		if !ok {
+			summary.caught = append(summary.caught, species)
+		}
+		if !dryRun && (!ok || when.Before(first)) {
+			dex.Seen[species] = true
+			dex.SpeciesCaught[species] = when
+		}
+	}
+	if !dryRun {
+		for _, species := range summary.seen {
+			dex.MarkSeen(species)
+		}
+	}
+
+	return summary
+}
+
+func commandImport(conf *config, args []string) error {
+	flags, positional, err := parseFlags(args, "policy")
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("Usage: import <file> [--policy keep-mine|keep-theirs|keep-both] [--dry-run]")
+	}
+
+	policy := "keep-mine"
+	if value, ok := flags["policy"]; ok {
+		policy = strings.ToLower(value)
+	}
+	if !containsString(importPolicies, policy) {
+		return fmt.Errorf("Unknown policy %s, use %s", policy, strings.Join(importPolicies, ", "))
+	}
+	dryRun := flags["dry-run"] == "true"
+
+	save, err := readSave(positional[0])
+	if err != nil {
+		return err
+	}
+
+	summary := mergePokedex(conf.pokedex, conf.storage, save.Pokedex, policy, dryRun)
+
+	verb := "Imported"
+	if dryRun {
+		verb = "Would import"
+	}
+	fmt.Fprintf(conf.out, "%s from %s:\n", verb, positional[0])
+	fmt.Fprintf(conf.out, "\t- %d new pokemon, sent to the PC\n", summary.added)
+	if summary.replaced > 0 {
+		fmt.Fprintf(conf.out, "\t- %d of your pokemon replaced with their copy\n", summary.replaced)
+	}
+	if summary.skipped > 0 {
+		fmt.Fprintf(conf.out, "\t- %d pokemon you already have skipped\n", summary.skipped)
+	}
+	fmt.Fprintf(conf.out, "\t- %d species newly seen, %d newly caught\n", len(summary.seen), len(summary.caught))
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func importTestDexes() (*Pokedex, *Storage, *Pokedex) {
+	caughtAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mine := NewPokedex()
+	store := NewStorage()
+	shared := &CaughtPokemon{Species: "pikachu", Level: 5, CaughtAt: caughtAt, IVs: map[string]int{"hp": 10}}
+	store.Store(mine.Add(shared))
+	mine.MarkCaught("pikachu", caughtAt)
+
+	theirs := NewPokedex()
+	theirCopy := *shared
+	theirCopy.Level = 30
+	theirs.Add(&theirCopy)
+	theirs.Add(&CaughtPokemon{Species: "eevee", Level: 10, CaughtAt: caughtAt.Add(time.Hour)})
+	theirs.MarkCaught("pikachu", caughtAt.Add(-time.Hour))
+	theirs.MarkCaught("eevee", caughtAt.Add(time.Hour))
+	theirs.MarkSeen("zubat")
+
+	return mine, store, theirs
+}
+
+func TestMergePokedex(t *testing.T) {
+	cases := []struct {
+		policy   string
+		added    int
+		replaced int
+		skipped  int
+		total    int
+		level    int
+	}{
+		{policy: "keep-mine", added: 1, skipped: 1, total: 2, level: 5},
+		{policy: "keep-theirs", added: 1, replaced: 1, total: 2, level: 30},
+		{policy: "keep-both", added: 2, total: 3, level: 5},
+	}
+
+	for _, c := range cases {
+		mine, store, theirs := importTestDexes()
+		summary := mergePokedex(mine, store, theirs, c.policy, false)
+
+		if summary.added != c.added || summary.replaced != c.replaced || summary.skipped != c.skipped {
+			t.Errorf("%s: unexpected summary %+v", c.policy, summary)
+		}
+		if len(mine.Caught) != c.total {
+			t.Errorf("%s: %d caught, expected %d", c.policy, len(mine.Caught), c.total)
+		}
+		if mine.Caught[1].Level != c.level {
+			t.Errorf("%s: #1 is level %d, expected %d", c.policy, mine.Caught[1].Level, c.level)
+		}
+		if len(store.Party) != 1 || len(store.Boxes[0]) != c.total-1 {
+			t.Errorf("%s: imports should go to the PC, party %v boxes %v", c.policy, store.Party, store.Boxes)
+		}
+		if len(summary.seen) != 2 || len(summary.caught) != 1 {
+			t.Errorf("%s: seen %v caught %v", c.policy, summary.seen, summary.caught)
+		}
+		if mine.Status("zubat") != "seen" || mine.Status("eevee") != "caught" {
+			t.Errorf("%s: species weren't merged", c.policy)
+		}
+		if !mine.SpeciesCaught["pikachu"].Equal(theirs.SpeciesCaught["pikachu"]) {
+			t.Errorf("%s: expected the earliest pikachu catch to be kept", c.policy)
+		}
+	}
+}
+
+func TestMergePokedexDryRun(t *testing.T) {
+	mine, store, theirs := importTestDexes()
+	summary := mergePokedex(mine, store, theirs, "keep-both", true)
+
+	if summary.added != 2 {
+		t.Errorf("expected 2 to be added, got %d", summary.added)
+	}
+	if len(mine.Caught) != 1 || len(store.Boxes[0]) != 0 || len(mine.Seen) != 1 {
+		t.Errorf("dry run changed the pokedex")
+	}
+}
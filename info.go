@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// commandInfo shows what the API knows about a species, for pokemon that
+// haven't been caught yet.
+func commandInfo(conf *config, args []string) error {
+	flags, positional, err := parseFlags(args, "version", "color")
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("Must pass a pokemon to the info command")
+	}
+
+	pokemon, err := conf.api.getPokemonForSpecies(positional[0])
+	if err != nil {
+		return err
+	}
+
+	if flags["sprite"] == "true" {
+		opts, err := spriteOptionsFromFlags(conf, flags)
+		if err != nil {
+			return err
+		}
+		err = printSprite(conf, pokemon, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(conf.out, "Name: %s\n", pokemon.Name)
+	fmt.Fprintf(conf.out, "ID: #%d\n", pokemon.ID)
+	status := conf.pokedex.Status(pokemon.Species.Name)
+	if len(status) == 0 {
+		status = "not seen"
+	}
+	fmt.Fprintf(conf.out, "Pokedex: %s\n", status)
+	fmt.Fprintf(conf.out, "Abilities: %s\n", speciesAbilities(pokemon))
+	fmt.Fprintf(conf.out, "Height: %d\n", pokemon.Height)
+	fmt.Fprintf(conf.out, "Weight: %d\n", pokemon.Weight)
+	fmt.Fprintln(conf.out, "Base stats:")
+	for _, stat := range pokemon.Stats {
+		fmt.Fprintf(conf.out, "\t-%s: %d\n", stat.Stat.Name, stat.BaseStat)
+	}
+	fmt.Fprintf(conf.out, "EV yield: %s\n", evYield(pokemon))
+	fmt.Fprintln(conf.out, "Types:")
+	for _, poketype := range pokemon.Types {
+		fmt.Fprintf(conf.out, "\t- %s\n", poketype.Type.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,441 @@
+package battle
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+type Move struct {
+	Name     string
+	Type     string
+	Class    string
+	Power    int
+	Accuracy int
+	PP       int
+	Priority int
+}
+
+type MoveSlot struct {
+	Move Move
+	PP   int
+}
+
+// Combatant is a pokemon taking part in a battle. Stats holds its actual
+// stats keyed by API stat name.
+type Combatant struct {
+	Name  string
+	Level int
+	Types []string
+	Stats map[string]int
+	HP    int
+	Moves []*MoveSlot
+}
+
+type Side struct {
+	Name   string
+	Team   []*Combatant
+	Active int
+}
+
+type ActionKind int
+
+const (
+	Fight ActionKind = iota
+	Switch
+	Run
+	Forfeit
+)
+
+// Action is what the player chooses to do for a turn. Index is the move slot
+// for Fight and the team slot for Switch.
+type Action struct {
+	Kind  ActionKind
+	Index int
+}
+
+type Result int
+
+const (
+	Ongoing Result = iota
+	Won
+	Lost
+	Escaped
+	Forfeited
+)
+
+// Defeat records an opponent pokemon fainting and the player's team slots
+// that battled it, which share the experience.
+type Defeat struct {
+	Opponent     int
+	Participants []int
+}
+
+type Battle struct {
+	Player   *Side
+	Opponent *Side
+	Wild     bool
+	Chart    TypeChart
+	Result   Result
+	Defeats  []Defeat
+
+	// MustSwitch is set when the player's active pokemon fainted and a
+	// replacement has to be picked before the battle continues.
+	MustSwitch bool
+
+	rng          *rand.Rand
+	runAttempts  int
+	participants []int
+}
+
+const critChance = 24
+
+// struggle is used when the active pokemon has no PP left in any move.
+var struggle = Move{
+	Name:     "struggle",
+	Class:    "physical",
+	Power:    50,
+	Accuracy: 0,
+}
+
+func NewCombatant(name string, level int, types []string, stats map[string]int, moves []Move) *Combatant {
+	c := Combatant{
+		Name:  name,
+		Level: level,
+		Types: types,
+		Stats: stats,
+		HP:    stats["hp"],
+	}
+	for _, move := range moves {
+		c.Moves = append(c.Moves, &MoveSlot{move, move.PP})
+	}
+	return &c
+}
+
+// New starts a battle. The same seed and actions always play out the same way.
+func New(player *Side, opponent *Side, wild bool, chart TypeChart, seed int64) (*Battle, error) {
+	if player.firstAble() < 0 {
+		return nil, errors.New("You have no pokemon able to battle")
+	}
+	if opponent.firstAble() < 0 {
+		return nil, errors.New("Your opponent has no pokemon able to battle")
+	}
+
+	player.Active = player.firstAble()
+	opponent.Active = opponent.firstAble()
+
+	return &Battle{
+		Player:       player,
+		Opponent:     opponent,
+		Wild:         wild,
+		Chart:        chart,
+		rng:          rand.New(rand.NewSource(seed)),
+		participants: []int{player.Active},
+	}, nil
+}
+
+func (c *Combatant) Fainted() bool {
+	return c.HP <= 0
+}
+
+func (c *Combatant) hasPP() bool {
+	for _, slot := range c.Moves {
+		if slot.PP > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Side) ActivePokemon() *Combatant {
+	return s.Team[s.Active]
+}
+
+func (s *Side) firstAble() int {
+	for i, c := range s.Team {
+		if !c.Fainted() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Turn plays out one turn and returns what happened, line by line.
+func (b *Battle) Turn(action Action) ([]string, error) {
+	if b.Result != Ongoing {
+		return nil, errors.New("The battle is over")
+	}
+	if b.MustSwitch && action.Kind != Switch {
+		return nil, errors.New("You must switch to another pokemon")
+	}
+
+	log := []string{}
+	player := b.Player.ActivePokemon()
+
+	switch action.Kind {
+	case Forfeit:
+		b.Result = Forfeited
+		return append(log, "You forfeited the battle."), nil
+	case Run:
+		if !b.Wild {
+			return nil, errors.New("You can't run from a trainer battle!")
+		}
+		if b.tryRun() {
+			b.Result = Escaped
+			return append(log, "Got away safely!"), nil
+		}
+		log = append(log, "Couldn't get away!")
+		log = b.useMove(log, b.Opponent, b.Player, b.opponentMove())
+		return b.checkFainted(log), nil
+	case Switch:
+		if action.Index < 0 || action.Index >= len(b.Player.Team) {
+			return nil, fmt.Errorf("There is no pokemon in slot %d", action.Index+1)
+		}
+		next := b.Player.Team[action.Index]
+		if next.Fainted() {
+			return nil, fmt.Errorf("%s has fainted and can't battle", next.Name)
+		}
+		if action.Index == b.Player.Active {
+			return nil, fmt.Errorf("%s is already battling", next.Name)
+		}
+
+		log = append(log, fmt.Sprintf("Come back, %s! Go, %s!", player.Name, next.Name))
+		b.Player.Active = action.Index
+		b.participate(action.Index)
+		if b.MustSwitch {
+			// Replacing a fainted pokemon doesn't cost a turn
+			b.MustSwitch = false
+			return log, nil
+		}
+		log = b.useMove(log, b.Opponent, b.Player, b.opponentMove())
+		return b.checkFainted(log), nil
+	case Fight:
+		playerMove, err := b.playerMove(action.Index)
+		if err != nil {
+			return nil, err
+		}
+		opponentMove := b.opponentMove()
+
+		first, second := b.Player, b.Opponent
+		firstMove, secondMove := playerMove, opponentMove
+		if b.opponentFirst(playerMove, opponentMove) {
+			first, second = second, first
+			firstMove, secondMove = secondMove, firstMove
+		}
+
+		log = b.useMove(log, first, second, firstMove)
+		if !second.ActivePokemon().Fainted() && !first.ActivePokemon().Fainted() {
+			log = b.useMove(log, second, first, secondMove)
+		}
+		return b.checkFainted(log), nil
+	}
+
+	return nil, errors.New("Unknown action")
+}
+
+func (b *Battle) playerMove(index int) (*MoveSlot, error) {
+	player := b.Player.ActivePokemon()
+	if !player.hasPP() {
+		return &MoveSlot{struggle, 1}, nil
+	}
+	if index < 0 || index >= len(player.Moves) {
+		return nil, fmt.Errorf("%s doesn't know a move in slot %d", player.Name, index+1)
+	}
+	slot := player.Moves[index]
+	if slot.PP <= 0 {
+		return nil, fmt.Errorf("There's no PP left for %s!", slot.Move.Name)
+	}
+	return slot, nil
+}
+
+// opponentMove picks the opponent's move. Wild pokemon choose at random while
+// trainers pick the move expected to do the most damage.
+func (b *Battle) opponentMove() *MoveSlot {
+	opponent := b.Opponent.ActivePokemon()
+	usable := []*MoveSlot{}
+	for _, slot := range opponent.Moves {
+		if slot.PP > 0 {
+			usable = append(usable, slot)
+		}
+	}
+	if len(usable) == 0 {
+		return &MoveSlot{struggle, 1}
+	}
+
+	if b.Wild {
+		return usable[b.rng.Intn(len(usable))]
+	}
+
+	target := b.Player.ActivePokemon()
+	best := usable[0]
+	bestScore := -1.0
+	for _, slot := range usable {
+		score := float64(slot.Move.Power) * b.Chart.Effectiveness(slot.Move.Type, target.Types...)
+		if hasType(opponent, slot.Move.Type) {
+			score *= 1.5
+		}
+		if score > bestScore {
+			best, bestScore = slot, score
+		}
+	}
+	return best
+}
+
+func (b *Battle) opponentFirst(playerMove *MoveSlot, opponentMove *MoveSlot) bool {
+	if playerMove.Move.Priority != opponentMove.Move.Priority {
+		return opponentMove.Move.Priority > playerMove.Move.Priority
+	}
+
+	playerSpeed := b.Player.ActivePokemon().Stats["speed"]
+	opponentSpeed := b.Opponent.ActivePokemon().Stats["speed"]
+	if playerSpeed != opponentSpeed {
+		return opponentSpeed > playerSpeed
+	}
+	return b.rng.Intn(2) == 0
+}
+
+// tryRun uses the escape formula from generation III onwards.
+func (b *Battle) tryRun() bool {
+	b.runAttempts++
+	playerSpeed := b.Player.ActivePokemon().Stats["speed"]
+	wildSpeed := b.Opponent.ActivePokemon().Stats["speed"]
+	if playerSpeed >= wildSpeed || wildSpeed == 0 {
+		return true
+	}
+
+	odds := (playerSpeed*128/wildSpeed + 30*b.runAttempts) % 256
+	return b.rng.Intn(256) < odds
+}
+
+func (b *Battle) useMove(log []string, attacker *Side, defender *Side, slot *MoveSlot) []string {
+	user := attacker.ActivePokemon()
+	target := defender.ActivePokemon()
+	move := slot.Move
+
+	slot.PP--
+	if move.Name == struggle.Name {
+		log = append(log, fmt.Sprintf("%s has no moves left!", user.Name))
+	}
+	log = append(log, fmt.Sprintf("%s used %s!", user.Name, move.Name))
+
+	if move.Accuracy > 0 && b.rng.Intn(100) >= move.Accuracy {
+		return append(log, fmt.Sprintf("%s's attack missed!", user.Name))
+	}
+
+	if move.Class == "status" || move.Power == 0 {
+		return append(log, "But nothing happened.")
+	}
+
+	effectiveness := b.Chart.Effectiveness(move.Type, target.Types...)
+	if effectiveness == 0 {
+		return append(log, fmt.Sprintf("It doesn't affect %s...", target.Name))
+	}
+
+	crit := b.rng.Intn(critChance) == 0
+	damage := b.damage(user, target, move, crit, effectiveness)
+	target.HP -= damage
+	if target.HP < 0 {
+		target.HP = 0
+	}
+
+	if crit {
+		log = append(log, "A critical hit!")
+	}
+	if effectiveness > 1 {
+		log = append(log, "It's super effective!")
+	} else if effectiveness < 1 {
+		log = append(log, "It's not very effective...")
+	}
+	log = append(log, fmt.Sprintf("%s took %d damage (%d/%d HP)", target.Name, damage, target.HP, target.Stats["hp"]))
+
+	if move.Name == struggle.Name {
+		recoil := max(user.Stats["hp"]/4, 1)
+		user.HP = max(user.HP-recoil, 0)
+		log = append(log, fmt.Sprintf("%s is hit with recoil!", user.Name))
+	}
+
+	return log
+}
+
+// damage applies the generation V onwards damage formula.
+func (b *Battle) damage(user *Combatant, target *Combatant, move Move, crit bool, effectiveness float64) int {
+	attack, defense := user.Stats["attack"], target.Stats["defense"]
+	if move.Class == "special" {
+		attack, defense = user.Stats["special-attack"], target.Stats["special-defense"]
+	}
+	defense = max(defense, 1)
+
+	damage := (2*user.Level/5+2)*move.Power*attack/defense/50 + 2
+	if crit {
+		damage = damage * 3 / 2
+	}
+	damage = damage * (85 + b.rng.Intn(16)) / 100
+	if hasType(user, move.Type) {
+		damage = damage * 3 / 2
+	}
+	damage = int(float64(damage) * effectiveness)
+
+	return max(damage, 1)
+}
+
+// checkFainted handles any pokemon that fainted this turn, sending out the
+// opponent's next pokemon or ending the battle.
+func (b *Battle) checkFainted(log []string) []string {
+	if b.Player.ActivePokemon().Fainted() {
+		log = append(log, fmt.Sprintf("%s fainted!", b.Player.ActivePokemon().Name))
+		if b.Player.firstAble() < 0 {
+			b.Result = Lost
+			return append(log, "You have no more pokemon that can fight!")
+		}
+		b.MustSwitch = true
+	}
+
+	if b.Opponent.ActivePokemon().Fainted() {
+		log = append(log, fmt.Sprintf("%s fainted!", b.Opponent.ActivePokemon().Name))
+		b.recordDefeat()
+		next := b.Opponent.firstAble()
+		if next < 0 {
+			b.Result = Won
+			b.MustSwitch = false
+			return log
+		}
+		b.Opponent.Active = next
+		if !b.Player.ActivePokemon().Fainted() {
+			b.participants = []int{b.Player.Active}
+		}
+		log = append(log, fmt.Sprintf("%s sent out %s!", b.Opponent.Name, b.Opponent.ActivePokemon().Name))
+	}
+
+	return log
+}
+
+func (b *Battle) participate(index int) {
+	for _, existing := range b.participants {
+		if existing == index {
+			return
+		}
+	}
+	b.participants = append(b.participants, index)
+}
+
+// recordDefeat credits the fainted opponent to every player pokemon that
+// battled it and is still standing.
+func (b *Battle) recordDefeat() {
+	participants := []int{}
+	for _, index := range b.participants {
+		if !b.Player.Team[index].Fainted() {
+			participants = append(participants, index)
+		}
+	}
+	b.Defeats = append(b.Defeats, Defeat{b.Opponent.Active, participants})
+	b.participants = []int{}
+}
+
+func hasType(c *Combatant, moveType string) bool {
+	for _, t := range c.Types {
+		if t == moveType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package battle
+
+import (
+	"fmt"
+	"testing"
+)
+
+var testChart = TypeChart{
+	"electric": {"water": 2, "ground": 0, "grass": 0.5},
+	"water":    {"fire": 2, "water": 0.5},
+}
+
+func testStats(hp int, speed int) map[string]int {
+	return map[string]int{
+		"hp":              hp,
+		"attack":          50,
+		"defense":         50,
+		"special-attack":  50,
+		"special-defense": 50,
+		"speed":           speed,
+	}
+}
+
+var thunderShock = Move{Name: "thunder-shock", Type: "electric", Class: "special", Power: 40, Accuracy: 100, PP: 30}
+
+var waterGun = Move{Name: "water-gun", Type: "water", Class: "special", Power: 40, Accuracy: 100, PP: 25}
+
+func newTestBattle(seed int64, wild bool) *Battle {
+	player := &Side{
+		Name: "You",
+		Team: []*Combatant{
+			NewCombatant("pikachu", 10, []string{"electric"}, testStats(30, 60), []Move{thunderShock}),
+			NewCombatant("squirtle", 10, []string{"water"}, testStats(30, 30), []Move{waterGun}),
+		},
+	}
+	opponent := &Side{
+		Name: "Wild psyduck",
+		Team: []*Combatant{
+			NewCombatant("psyduck", 10, []string{"water"}, testStats(40, 40), []Move{waterGun}),
+		},
+	}
+
+	b, err := New(player, opponent, wild, testChart, seed)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestEffectiveness(t *testing.T) {
+	cases := []struct {
+		attack   string
+		defend   []string
+		expected float64
+	}{
+		{attack: "electric", defend: []string{"water"}, expected: 2},
+		{attack: "electric", defend: []string{"water", "grass"}, expected: 1},
+		{attack: "electric", defend: []string{"ground", "water"}, expected: 0},
+		{attack: "water", defend: []string{"normal"}, expected: 1},
+		{attack: "", defend: []string{"water"}, expected: 1},
+	}
+
+	for _, c := range cases {
+		actual := testChart.Effectiveness(c.attack, c.defend...)
+		if actual != c.expected {
+			t.Errorf("%s vs %v: actual %v != expected %v", c.attack, c.defend, actual, c.expected)
+		}
+	}
+}
+
+func TestDeterministicBattle(t *testing.T) {
+	play := func() string {
+		b := newTestBattle(42, true)
+		out := ""
+		for b.Result == Ongoing {
+			action := Action{Kind: Fight}
+			if b.MustSwitch {
+				action = Action{Kind: Switch, Index: 1}
+			}
+			log, err := b.Turn(action)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			out += fmt.Sprintln(log)
+		}
+		return out
+	}
+
+	first := play()
+	second := play()
+	if first != second {
+		t.Errorf("battles with the same seed differ:\n%s\n%s", first, second)
+	}
+}
+
+func TestDamage(t *testing.T) {
+	b := newTestBattle(1, true)
+	pikachu := b.Player.Team[0]
+	psyduck := b.Opponent.Team[0]
+
+	// Base damage is (2*10/5+2)*40*50/50/50+2 = 6, then STAB and 2x
+	for i := 0; i < 100; i++ {
+		damage := b.damage(pikachu, psyduck, thunderShock, false, 2)
+		if damage < 14 || damage > 18 {
+			t.Fatalf("damage %v outside of expected range 14-18", damage)
+		}
+	}
+
+	if damage := b.damage(pikachu, psyduck, waterGun, false, 0.5); damage != 2 {
+		t.Errorf("resisted damage: actual %v != expected 2", damage)
+	}
+}
+
+func TestFaintAndSwitch(t *testing.T) {
+	b := newTestBattle(7, true)
+	b.Player.Team[0].HP = 1
+	b.Opponent.Team[0].Stats["speed"] = 100
+
+	_, err := b.Turn(Action{Kind: Fight})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !b.MustSwitch {
+		t.Fatalf("expected to have to switch after fainting")
+	}
+
+	if _, err := b.Turn(Action{Kind: Fight}); err == nil {
+		t.Errorf("expected an error fighting with a fainted pokemon")
+	}
+	if _, err := b.Turn(Action{Kind: Switch, Index: 0}); err == nil {
+		t.Errorf("expected an error switching to a fainted pokemon")
+	}
+
+	_, err = b.Turn(Action{Kind: Switch, Index: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Player.ActivePokemon().Name != "squirtle" || b.MustSwitch {
+		t.Errorf("expected squirtle to be sent out")
+	}
+}
+
+func TestRun(t *testing.T) {
+	b := newTestBattle(3, true)
+	_, err := b.Turn(Action{Kind: Run})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Result != Escaped {
+		t.Errorf("a faster pokemon should always escape")
+	}
+
+	b = newTestBattle(3, false)
+	if _, err := b.Turn(Action{Kind: Run}); err == nil {
+		t.Errorf("expected an error running from a trainer")
+	}
+}
+
+func TestPP(t *testing.T) {
+	b := newTestBattle(5, true)
+	b.Opponent.Team[0].HP = 1000
+	b.Opponent.Team[0].Stats["hp"] = 1000
+	b.Player.Team[0].Moves[0].PP = 1
+
+	_, err := b.Turn(Action{Kind: Fight})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Player.Team[0].Moves[0].PP != 0 {
+		t.Errorf("expected the move to use up its PP")
+	}
+
+	log, err := b.Turn(Action{Kind: Fight})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(log) < 2 || log[1] != "pikachu used struggle!" {
+		t.Errorf("expected pikachu to struggle, got %v", log)
+	}
+}
+
+func TestDefeats(t *testing.T) {
+	b := newTestBattle(11, true)
+	b.Opponent.Team[0].HP = 1
+
+	_, err := b.Turn(Action{Kind: Switch, Index: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = b.Turn(Action{Kind: Fight})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if b.Result != Won {
+		t.Fatalf("expected to win the battle")
+	}
+	if len(b.Defeats) != 1 || len(b.Defeats[0].Participants) != 2 {
+		t.Errorf("expected both pokemon to share the defeat, got %v", b.Defeats)
+	}
+}
@@ -0,0 +1,24 @@
+package battle
+
+// TypeChart maps an attacking type to the damage multiplier it has against
+// each defending type. Missing entries are neutral.
+type TypeChart map[string]map[string]float64
+
+func (t TypeChart) Set(attack string, defend string, multiplier float64) {
+	if _, ok := t[attack]; !ok {
+		t[attack] = map[string]float64{}
+	}
+	t[attack][defend] = multiplier
+}
+
+// Effectiveness multiplies the matchups against every defending type, so a
+// dual typed pokemon can take 4x or 0.25x damage.
+func (t TypeChart) Effectiveness(attack string, defenders ...string) float64 {
+	multiplier := 1.0
+	for _, defend := range defenders {
+		if value, ok := t[attack][defend]; ok {
+			multiplier *= value
+		}
+	}
+	return multiplier
+}
@@ -0,0 +1,80 @@
+package pokedexapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mikeheiberger/pokedexcli/internal/pokecache"
+)
+
+// Asset is a binary file such as a sprite or a cry along with its type.
+type Asset struct {
+	ContentType string
+	Data        []byte
+}
+
+// AssetCache keeps assets in a cache of their own so that big images and
+// audio files never push API pages out of the JSON cache.
+type AssetCache struct {
+	cache *pokecache.Cache
+}
+
+func NewAssetCache(interval time.Duration) *AssetCache {
+	return &AssetCache{pokecache.NewCache(interval)}
+}
+
+// Get returns the asset at url, downloading it the first time.
+func (a *AssetCache) Get(url string) (Asset, error) {
+	if entry, ok := a.cache.Get(url); ok {
+		return decodeAsset(entry), nil
+	}
+
+	asset, err := FetchAsset(url)
+	if err != nil {
+		return Asset{}, err
+	}
+
+	a.cache.Add(url, encodeAsset(asset))
+	return asset, nil
+}
+
+func FetchAsset(url string) (Asset, error) {
+	res, err := http.Get(url)
+	if err != nil {
+		return Asset{}, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	if err != nil {
+		return Asset{}, err
+	}
+
+	if res.StatusCode > 299 {
+		return Asset{}, fmt.Errorf("Response failed with status code: %d", res.StatusCode)
+	}
+
+	// Raw file hosts often don't know the type, so sniff it from the data
+	contentType := res.Header.Get("Content-Type")
+	if len(contentType) == 0 || strings.HasPrefix(contentType, "application/octet-stream") || strings.HasPrefix(contentType, "text/plain") {
+		contentType = http.DetectContentType(body)
+	}
+
+	return Asset{contentType, body}, nil
+}
+
+// encodeAsset stores the content type on the first line, which is safe
+// because content types never contain a newline.
+func encodeAsset(asset Asset) []byte {
+	return append([]byte(asset.ContentType+"\n"), asset.Data...)
+}
+
+func decodeAsset(entry []byte) Asset {
+	contentType, data, _ := bytes.Cut(entry, []byte("\n"))
+	return Asset{string(contentType), data}
+}
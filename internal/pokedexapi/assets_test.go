@@ -0,0 +1,76 @@
+package pokedexapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAssetCache(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\n\nbinary")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(png)
+	}))
+	defer server.Close()
+
+	assets := NewAssetCache(time.Minute)
+	for i := 0; i < 2; i++ {
+		asset, err := assets.Get(server.URL + "/25.png")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if asset.ContentType != "image/png" {
+			t.Errorf("content type: actual %s != expected image/png", asset.ContentType)
+		}
+		if string(asset.Data) != string(png) {
+			t.Errorf("data: actual %q != expected %q", asset.Data, png)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestFetchAssetStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := FetchAsset(server.URL); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestAssetCacheAudio(t *testing.T) {
+	// Cries are OGG files served from a raw file host without a useful type
+	ogg := []byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00\x01vorbis\x00\xff")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write(ogg)
+	}))
+	defer server.Close()
+
+	assets := NewAssetCache(time.Minute)
+	for i := 0; i < 2; i++ {
+		asset, err := assets.Get(server.URL + "/25.ogg")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if asset.ContentType != "application/ogg" {
+			t.Errorf("content type: actual %s != expected application/ogg", asset.ContentType)
+		}
+		if string(asset.Data) != string(ogg) {
+			t.Errorf("data: actual %q != expected %q", asset.Data, ogg)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
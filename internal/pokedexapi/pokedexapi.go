@@ -1,11 +1,15 @@
 package pokedexapi
 
 import(
+	"errors"
     "fmt"
 	"net/http"
 	"io"
 )
 
+// ErrNotFound is returned when the API has nothing at a URL, such as a
+// misspelled pokemon.
+var ErrNotFound = errors.New("Not found")
 
 func QueryPokedexApi(url string) ([]byte, error) {
 	res, err := http.Get(url)
@@ -20,6 +24,9 @@ func QueryPokedexApi(url string) ([]byte, error) {
 		return nil, err
 	}
 
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, url)
+	}
 	if res.StatusCode > 299 {
 		return nil, fmt.Errorf("Response failed with status code: %d and\nbody: %s\n", res.StatusCode, body)
 	}
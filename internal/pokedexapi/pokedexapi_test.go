@@ -0,0 +1,37 @@
+package pokedexapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQueryPokedexApi(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pokemon/pikachu/":
+			w.Write([]byte(`{"name": "pikachu"}`))
+		case "/pokemon/broken/":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	body, err := QueryPokedexApi(server.URL + "/pokemon/pikachu/")
+	if err != nil || string(body) != `{"name": "pikachu"}` {
+		t.Errorf("actual %q, %v != expected pikachu", body, err)
+	}
+
+	_, err = QueryPokedexApi(server.URL + "/pokemon/pikachoo/")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("actual %v != expected a not found error", err)
+	}
+
+	_, err = QueryPokedexApi(server.URL + "/pokemon/broken/")
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("actual %v != expected a server error", err)
+	}
+}
@@ -0,0 +1,250 @@
+// Package repl reads commands from an input, runs them against a registry
+// and writes their output, so a command line can be driven from a terminal
+// or from a test.
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownCommand = errors.New("Unknown command")
+
+// ErrExit is returned by a command to end the session.
+var ErrExit = errors.New("exit")
+
+// maxExpansions limits how deeply aliases and macros can expand into each
+// other, which stops one that uses itself.
+const maxExpansions = 10
+
+// Command is something that can be typed at the prompt. Its callback gets
+// the session's state and the words typed after its name.
+type Command[S any] struct {
+	Name        string
+	Description string
+	Callback    func(S, []string) error
+	// KeepCase commands get their arguments as typed, for things like file paths
+	KeepCase bool
+}
+
+// Registry holds the commands of a session by name, along with built in
+// aliases for them.
+type Registry[S any] struct {
+	commands map[string]Command[S]
+	aliases  map[string]string
+}
+
+func NewRegistry[S any]() *Registry[S] {
+	return &Registry[S]{commands: map[string]Command[S]{}, aliases: map[string]string{}}
+}
+
+// Register adds a command. Registering two commands under one name is a
+// programming error, so it panics.
+func (r *Registry[S]) Register(command Command[S]) {
+	if len(command.Name) == 0 || command.Callback == nil {
+		panic("repl: commands need a name and a callback")
+	}
+	if _, ok := r.Lookup(command.Name); ok {
+		panic("repl: command " + command.Name + " registered twice")
+	}
+	r.commands[command.Name] = command
+}
+
+// Alias lets a command also be run by another name.
+func (r *Registry[S]) Alias(alias string, name string) {
+	if _, ok := r.commands[name]; !ok {
+		panic("repl: alias " + alias + " for unknown command " + name)
+	}
+	if _, ok := r.Lookup(alias); ok {
+		panic("repl: alias " + alias + " is already taken")
+	}
+	r.aliases[alias] = name
+}
+
+// Lookup finds a command by its name or one of its aliases.
+func (r *Registry[S]) Lookup(name string) (Command[S], bool) {
+	if target, ok := r.aliases[name]; ok {
+		name = target
+	}
+	command, ok := r.commands[name]
+	return command, ok
+}
+
+// IsCommand reports whether name is a command's own name rather than an
+// alias.
+func (r *Registry[S]) IsCommand(name string) bool {
+	_, ok := r.commands[name]
+	return ok
+}
+
+// Aliases returns the built in aliases and the commands they stand for.
+func (r *Registry[S]) Aliases() map[string]string {
+	aliases := map[string]string{}
+	for alias, name := range r.aliases {
+		aliases[alias] = name
+	}
+	return aliases
+}
+
+// Commands lists every command sorted by name.
+func (r *Registry[S]) Commands() []Command[S] {
+	commands := []Command[S]{}
+	for _, command := range r.commands {
+		commands = append(commands, command)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+	return commands
+}
+
+// Session runs the commands of a registry with lines read from In, writing
+// prompts and errors to Out.
+type Session[S any] struct {
+	Registry *Registry[S]
+	State    S
+	In       io.Reader
+	Out      io.Writer
+	Prompt   string
+
+	// Before can refuse to run a command by returning an error
+	Before func(Command[S]) error
+	// After runs once a command has succeeded
+	After func(Command[S]) error
+
+	// Aliases and Macros return the user's own shortcuts, which are checked
+	// before the registry. An alias stands in for the start of a line, and
+	// a macro runs commands separated by ; with $1, $2... replaced by its
+	// arguments and $* by all of them.
+	Aliases func() map[string]string
+	Macros  func() map[string]string
+}
+
+func CleanInput(text string) []string {
+	split := strings.Fields(strings.ToLower(text))
+	return split
+}
+
+// Execute runs one line of input. Blank lines do nothing.
+func (s *Session[S]) Execute(line string) error {
+	return s.execute(line, 0)
+}
+
+func (s *Session[S]) execute(line string, depth int) error {
+	input := CleanInput(line)
+	if len(input) == 0 {
+		return nil
+	}
+	words := strings.Fields(line)
+
+	body, isMacro := userShortcut(s.Macros, input[0])
+	expansion, isAlias := userShortcut(s.Aliases, input[0])
+	if (isMacro || isAlias) && depth >= maxExpansions {
+		return fmt.Errorf("%s expands too many times, does it use itself?", input[0])
+	}
+
+	if isMacro {
+		lines, err := ExpandMacro(body, words[1:])
+		if err != nil {
+			return fmt.Errorf("%s: %v", input[0], err)
+		}
+		for _, expanded := range lines {
+			err := s.execute(expanded, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if isAlias {
+		return s.execute(strings.Join(append([]string{expansion}, words[1:]...), " "), depth+1)
+	}
+
+	command, ok := s.Registry.Lookup(input[0])
+	if !ok {
+		return ErrUnknownCommand
+	}
+
+	args := input[1:]
+	if command.KeepCase {
+		args = words[1:]
+	}
+
+	if s.Before != nil {
+		err := s.Before(command)
+		if err != nil {
+			return err
+		}
+	}
+	err := command.Callback(s.State, args)
+	if err != nil {
+		return err
+	}
+	if s.After != nil {
+		return s.After(command)
+	}
+	return nil
+}
+
+func userShortcut(shortcuts func() map[string]string, name string) (string, bool) {
+	if shortcuts == nil {
+		return "", false
+	}
+	value, ok := shortcuts()[name]
+	return value, ok
+}
+
+// ExpandMacro fills in a macro's parameters and splits it into the lines to
+// run. Every parameter it uses must be given.
+func ExpandMacro(body string, args []string) ([]string, error) {
+	lines := []string{}
+	for _, command := range strings.Split(body, ";") {
+		words := []string{}
+		for _, word := range strings.Fields(command) {
+			if word == "$*" {
+				words = append(words, args...)
+				continue
+			}
+			if number, ok := strings.CutPrefix(word, "$"); ok {
+				n, err := strconv.Atoi(number)
+				if err == nil && n >= 1 {
+					if n > len(args) {
+						return nil, fmt.Errorf("needs at least %d arguments", n)
+					}
+					word = args[n-1]
+				}
+			}
+			words = append(words, word)
+		}
+		if len(words) > 0 {
+			lines = append(lines, strings.Join(words, " "))
+		}
+	}
+	return lines, nil
+}
+
+// Run prompts for and executes lines until the input ends or a command
+// returns ErrExit. Other errors from commands are written out and don't stop
+// the session.
+func (s *Session[S]) Run() error {
+	scanner := bufio.NewScanner(s.In)
+	for {
+		fmt.Fprint(s.Out, s.Prompt)
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		err := s.Execute(scanner.Text())
+		if errors.Is(err, ErrExit) {
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintln(s.Out, err.Error())
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package repl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCleanInput(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			input:    " hello world ",
+			expected: []string{"hello", "world"},
+		},
+		{
+			input:    " cool test    for cool people  ",
+			expected: []string{"cool", "test", "for", "cool", "people"},
+		},
+		{
+			input:    "single",
+			expected: []string{"single"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := CleanInput(c.input)
+		if len(actual) != len(c.expected) {
+			t.Errorf("actual length: %v != expected length: %v", len(actual), len(c.expected))
+		}
+
+		for i := range actual {
+			word := actual[i]
+			expectedWord := c.expected[i]
+			if word != expectedWord {
+				t.Errorf("word: %v != expectedWord: %v", word, expectedWord)
+			}
+		}
+	}
+}
+
+type counter struct {
+	calls int
+	out   *bytes.Buffer
+}
+
+func newTestSession(input string) (*Session[*counter], *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	registry := NewRegistry[*counter]()
+	registry.Register(Command[*counter]{
+		Name: "echo",
+		Callback: func(c *counter, args []string) error {
+			c.calls++
+			fmt.Fprintln(c.out, strings.Join(args, " "))
+			return nil
+		},
+	})
+	registry.Register(Command[*counter]{
+		Name:     "path",
+		KeepCase: true,
+		Callback: func(c *counter, args []string) error {
+			c.calls++
+			fmt.Fprintln(c.out, args[0])
+			return nil
+		},
+	})
+	registry.Register(Command[*counter]{
+		Name: "fail",
+		Callback: func(c *counter, args []string) error {
+			return errors.New("failed")
+		},
+	})
+
+	return &Session[*counter]{
+		Registry: registry,
+		State:    &counter{out: out},
+		In:       strings.NewReader(input),
+		Out:      out,
+		Prompt:   "> ",
+	}, out
+}
+
+func TestRegistry(t *testing.T) {
+	session, _ := newTestSession("")
+
+	names := []string{}
+	for _, command := range session.Registry.Commands() {
+		names = append(names, command.Name)
+	}
+	if strings.Join(names, ",") != "echo,fail,path" {
+		t.Errorf("actual %v != expected [echo fail path]", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected registering a name twice to panic")
+		}
+	}()
+	session.Registry.Register(Command[*counter]{Name: "echo", Callback: func(*counter, []string) error { return nil }})
+}
+
+func TestSessionRun(t *testing.T) {
+	session, out := newTestSession("Echo Hello World\n\npath Some/File.csv\nfail\nmissing\n")
+	afters := 0
+	session.After = func(Command[*counter]) error {
+		afters++
+		return nil
+	}
+
+	err := session.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "> hello world\n> > Some/File.csv\n> failed\n> Unknown command\n> "
+	if out.String() != expected {
+		t.Errorf("actual %q != expected %q", out.String(), expected)
+	}
+	if session.State.calls != 2 || afters != 2 {
+		t.Errorf("actual %d calls and %d afters != expected 2 of each", session.State.calls, afters)
+	}
+}
+
+func TestSessionBefore(t *testing.T) {
+	session, _ := newTestSession("")
+	session.Before = func(command Command[*counter]) error {
+		if command.Name != "path" {
+			return errors.New("not now")
+		}
+		return nil
+	}
+
+	err := session.Execute("echo hi")
+	if err == nil || err.Error() != "not now" {
+		t.Errorf("actual %v != expected not now", err)
+	}
+	if session.State.calls != 0 {
+		t.Errorf("expected the refused command not to run")
+	}
+	if !errors.Is(session.Execute("nope"), ErrUnknownCommand) {
+		t.Errorf("expected an unknown command error")
+	}
+}
+
+func TestExpandMacro(t *testing.T) {
+	cases := []struct {
+		body     string
+		args     []string
+		expected []string
+	}{
+		{"explore $1; catch $2", []string{"forest", "pikachu"}, []string{"explore forest", "catch pikachu"}},
+		{"export $*;;", []string{"csv", "Out.csv"}, []string{"export csv Out.csv"}},
+		{"help", []string{"ignored"}, []string{"help"}},
+		{"cost $5", []string{}, nil},
+	}
+
+	for _, c := range cases {
+		actual, err := ExpandMacro(c.body, c.args)
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("%s: expected an error", c.body)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.body, err)
+		}
+		if strings.Join(actual, "|") != strings.Join(c.expected, "|") {
+			t.Errorf("%s: actual %v != expected %v", c.body, actual, c.expected)
+		}
+	}
+}
+
+func TestSessionShortcuts(t *testing.T) {
+	session, out := newTestSession("")
+	session.Registry.Alias("p", "path")
+	aliases := map[string]string{"say": "echo hi", "loop": "loop"}
+	macros := map[string]string{"twice": "say $1; p $2", "p": "echo shadowed"}
+	session.Aliases = func() map[string]string { return aliases }
+	session.Macros = func() map[string]string { return macros }
+
+	err := session.Execute("Say There")
+	if err != nil || out.String() != "hi there\n" {
+		t.Errorf("alias: actual %q, %v != expected \"hi there\\n\"", out.String(), err)
+	}
+
+	out.Reset()
+	err = session.Execute("twice you Some/File")
+	if err != nil || out.String() != "hi you\nshadowed\n" {
+		t.Errorf("macro: actual %q, %v", out.String(), err)
+	}
+
+	delete(macros, "p")
+	out.Reset()
+	err = session.Execute("twice you Some/File")
+	if err != nil || out.String() != "hi you\nSome/File\n" {
+		t.Errorf("built in alias: actual %q, %v", out.String(), err)
+	}
+
+	if err := session.Execute("twice"); err == nil {
+		t.Errorf("expected a macro missing its arguments to fail")
+	}
+	if err := session.Execute("loop"); err == nil {
+		t.Errorf("expected an alias using itself to fail")
+	}
+}
+
+func TestSessionExit(t *testing.T) {
+	session, out := newTestSession("echo one\nbye\necho two\n")
+	session.Registry.Register(Command[*counter]{
+		Name: "bye",
+		Callback: func(c *counter, args []string) error {
+			return ErrExit
+		},
+	})
+
+	err := session.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "> one\n> " {
+		t.Errorf("actual %q != expected %q", out.String(), "> one\n> ")
+	}
+}
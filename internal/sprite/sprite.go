@@ -0,0 +1,153 @@
+package sprite
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// Mode is how much color the terminal can show.
+type Mode int
+
+const (
+	TrueColor Mode = iota
+	Color256
+	ASCII
+)
+
+// asciiRamp goes from the darkest pixels to the lightest.
+const asciiRamp = "@%#*+=-:. "
+
+const reset = "\x1b[0m"
+
+func ParseMode(name string) (Mode, error) {
+	switch name {
+	case "truecolor", "24bit":
+		return TrueColor, nil
+	case "256":
+		return Color256, nil
+	case "ascii":
+		return ASCII, nil
+	}
+	return TrueColor, fmt.Errorf("Unknown color mode %s, use truecolor, 256 or ascii", name)
+}
+
+// DetectMode picks a mode from the COLORTERM and TERM environment variables.
+func DetectMode(colorterm string, term string) Mode {
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		return TrueColor
+	}
+	if len(term) == 0 || term == "dumb" {
+		return ASCII
+	}
+	return Color256
+}
+
+// Bounds is the smallest rectangle holding every visible pixel, so the empty
+// border most sprites have isn't drawn.
+func Bounds(img image.Image) image.Rectangle {
+	bounds := img.Bounds()
+	visible := image.Rectangle{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if opaque(img.At(x, y)) {
+				visible = visible.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return visible
+}
+
+// Render draws the image with one character for every two rows of pixels.
+// The color modes use the upper half block with the top pixel as foreground
+// and the bottom pixel as background.
+func Render(img image.Image, mode Mode) []string {
+	bounds := Bounds(img)
+	lines := []string{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		line := ""
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			top := img.At(x, y)
+			var bottom color.Color = color.Transparent
+			if y+1 < bounds.Max.Y {
+				bottom = img.At(x, y+1)
+			}
+			if mode == ASCII {
+				line += asciiCell(top, bottom)
+			} else {
+				line += colorCell(top, bottom, mode)
+			}
+		}
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	return lines
+}
+
+func opaque(c color.Color) bool {
+	_, _, _, a := c.RGBA()
+	return a >= 0x8000
+}
+
+func rgb(c color.Color) (uint8, uint8, uint8) {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return nrgba.R, nrgba.G, nrgba.B
+}
+
+// xterm256 finds the closest color in the 6x6x6 cube of the 256 color palette.
+func xterm256(c color.Color) int {
+	r, g, b := rgb(c)
+	level := func(value uint8) int {
+		return (int(value)*5 + 127) / 255
+	}
+	return 16 + 36*level(r) + 6*level(g) + level(b)
+}
+
+func foreground(c color.Color, mode Mode) string {
+	if mode == Color256 {
+		return fmt.Sprintf("\x1b[38;5;%dm", xterm256(c))
+	}
+	r, g, b := rgb(c)
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", r, g, b)
+}
+
+func background(c color.Color, mode Mode) string {
+	if mode == Color256 {
+		return fmt.Sprintf("\x1b[48;5;%dm", xterm256(c))
+	}
+	r, g, b := rgb(c)
+	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm", r, g, b)
+}
+
+func colorCell(top color.Color, bottom color.Color, mode Mode) string {
+	switch {
+	case opaque(top) && opaque(bottom):
+		return foreground(top, mode) + background(bottom, mode) + "▀" + reset
+	case opaque(top):
+		return foreground(top, mode) + "▀" + reset
+	case opaque(bottom):
+		return foreground(bottom, mode) + "▄" + reset
+	}
+	return " "
+}
+
+func asciiCell(top color.Color, bottom color.Color) string {
+	total := 0.0
+	count := 0
+	for _, c := range []color.Color{top, bottom} {
+		if !opaque(c) {
+			continue
+		}
+		r, g, b := rgb(c)
+		total += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+		count++
+	}
+	if count == 0 {
+		return " "
+	}
+
+	luminance := total / float64(count) / 255
+	// The last character is a space, which is kept for transparent pixels
+	index := int(luminance * float64(len(asciiRamp)-2))
+	return string(asciiRamp[index])
+}
@@ -0,0 +1,85 @@
+package sprite
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+var red = color.NRGBA{255, 0, 0, 255}
+
+var blue = color.NRGBA{0, 0, 255, 255}
+
+var black = color.NRGBA{0, 0, 0, 255}
+
+var white = color.NRGBA{255, 255, 255, 255}
+
+// testImage has a transparent border around a 2x3 picture.
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 6, 6))
+	img.Set(2, 1, red)
+	img.Set(3, 1, black)
+	img.Set(2, 2, blue)
+	img.Set(3, 2, white)
+	img.Set(2, 3, red)
+	return img
+}
+
+func TestBounds(t *testing.T) {
+	expected := image.Rect(2, 1, 4, 4)
+	actual := Bounds(testImage())
+	if actual != expected {
+		t.Errorf("actual %v != expected %v", actual, expected)
+	}
+}
+
+func TestRenderTrueColor(t *testing.T) {
+	expected := []string{
+		"\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀\x1b[0m\x1b[38;2;0;0;0m\x1b[48;2;255;255;255m▀\x1b[0m",
+		"\x1b[38;2;255;0;0m▀\x1b[0m",
+	}
+	actual := Render(testImage(), TrueColor)
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("actual %q != expected %q", actual, expected)
+	}
+}
+
+func TestRender256(t *testing.T) {
+	expected := []string{
+		"\x1b[38;5;196m\x1b[48;5;21m▀\x1b[0m\x1b[38;5;16m\x1b[48;5;231m▀\x1b[0m",
+		"\x1b[38;5;196m▀\x1b[0m",
+	}
+	actual := Render(testImage(), Color256)
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("actual %q != expected %q", actual, expected)
+	}
+}
+
+func TestRenderASCII(t *testing.T) {
+	expected := []string{"%+", "#"}
+	actual := Render(testImage(), ASCII)
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("actual %q != expected %q", actual, expected)
+	}
+}
+
+func TestDetectMode(t *testing.T) {
+	cases := []struct {
+		colorterm string
+		term      string
+		expected  Mode
+	}{
+		{colorterm: "truecolor", term: "xterm-256color", expected: TrueColor},
+		{colorterm: "", term: "xterm-256color", expected: Color256},
+		{colorterm: "", term: "dumb", expected: ASCII},
+		{colorterm: "", term: "", expected: ASCII},
+	}
+
+	for _, c := range cases {
+		actual := DetectMode(c.colorterm, c.term)
+		if actual != c.expected {
+			t.Errorf("%q %q: actual %v != expected %v", c.colorterm, c.term, actual, c.expected)
+		}
+	}
+}
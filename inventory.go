@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Inventory is the bag of items a trainer carries, counted by item name.
+type Inventory struct {
+	Items map[string]int `json:"items"`
+}
+
+// stonePrizes are the evolution stones a trainer battle can be won with,
+// each with its chance out of 100.
+var stonePrizes = []struct {
+	item   string
+	chance int
+}{
+	{"fire-stone", 4},
+	{"water-stone", 4},
+	{"thunder-stone", 4},
+	{"leaf-stone", 4},
+	{"moon-stone", 4},
+}
+
+func NewInventory() *Inventory {
+	return &Inventory{Items: map[string]int{}}
+}
+
+func (inv *Inventory) Add(item string, count int) {
+	inv.Items[item] += count
+}
+
+func (inv *Inventory) Count(item string) int {
+	return inv.Items[item]
+}
+
+// Use takes one of an item out of the bag.
+func (inv *Inventory) Use(item string) error {
+	if inv.Items[item] <= 0 {
+		return fmt.Errorf("You don't have a %s", item)
+	}
+	inv.Items[item]--
+	if inv.Items[item] == 0 {
+		delete(inv.Items, item)
+	}
+	return nil
+}
+
+// battlePrize is what winning a battle earns: after a trainer battle maybe a
+// stone, picked by a roll out of 100. Wild battles earn nothing.
+func battlePrize(wild bool, roll int) map[string]int {
+	prize := map[string]int{}
+	if wild {
+		return prize
+	}
+	for _, stone := range stonePrizes {
+		if roll < stone.chance {
+			prize[stone.item]++
+			break
+		}
+		roll -= stone.chance
+	}
+	return prize
+}
+
+// awardPrize adds a prize to the bag, saying what was received.
+func awardPrize(conf *config, prize map[string]int) {
+	items := []string{}
+	for item := range prize {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+
+	for _, item := range items {
+		conf.inventory.Add(item, prize[item])
+		fmt.Fprintf(conf.out, "You received %s x%d\n", item, prize[item])
+	}
+}
+
+func commandInventory(conf *config, args []string) error {
+	if len(args) > 0 {
+		return errors.New("Usage: inventory")
+	}
+	if len(conf.inventory.Items) == 0 {
+		fmt.Fprintln(conf.out, "Your bag is empty, win trainer battles to earn items")
+		return nil
+	}
+
+	items := []string{}
+	for item := range conf.inventory.Items {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+
+	fmt.Fprintln(conf.out, "Your bag:")
+	for _, item := range items {
+		fmt.Fprintf(conf.out, "\t- %s x%d\n", item, conf.inventory.Items[item])
+	}
+	return nil
+}
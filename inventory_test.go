@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestInventoryUse(t *testing.T) {
+	inventory := NewInventory()
+	if len(inventory.Items) != 0 {
+		t.Errorf("expected a new bag to be empty, got %v", inventory.Items)
+	}
+
+	inventory.Add("fire-stone", 1)
+	if err := inventory.Use("fire-stone"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := inventory.Items["fire-stone"]; ok {
+		t.Errorf("expected a used up item to leave the bag")
+	}
+	if err := inventory.Use("fire-stone"); err == nil {
+		t.Errorf("expected an error using an item that's gone")
+	}
+}
+
+func TestBattlePrize(t *testing.T) {
+	cases := []struct {
+		wild     bool
+		roll     int
+		expected map[string]int
+	}{
+		{true, 0, map[string]int{}},
+		{false, 0, map[string]int{"fire-stone": 1}},
+		{false, 4, map[string]int{"water-stone": 1}},
+		{false, 19, map[string]int{"moon-stone": 1}},
+		{false, 20, map[string]int{}},
+	}
+
+	for _, c := range cases {
+		actual := battlePrize(c.wild, c.roll)
+		if len(actual) != len(c.expected) {
+			t.Errorf("roll %d: actual %v != expected %v", c.roll, actual, c.expected)
+			continue
+		}
+		for item, count := range c.expected {
+			if actual[item] != count {
+				t.Errorf("roll %d: actual %v != expected %v", c.roll, actual, c.expected)
+			}
+		}
+	}
+}
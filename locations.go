@@ -0,0 +1,242 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type LocationResponse struct {
+	ID     int             `json:"id"`
+	Name   string          `json:"name"`
+	Region *namedResource  `json:"region"`
+	Areas  []namedResource `json:"areas"`
+}
+
+// locationPageSize is how many entries map shows until --limit changes it.
+// It matches the default page size of the API.
+const locationPageSize = 20
+
+func (c *apiClient) getLocation(name string) (LocationResponse, error) {
+	var location LocationResponse
+	err := c.getJsonResource("https://pokeapi.co/api/v2/location/"+name+"/", &location)
+	return location, err
+}
+
+func regionName(location LocationResponse) string {
+	if location.Region == nil {
+		return "unknown region"
+	}
+	return location.Region.Name
+}
+
+func locationAreaPageUrl(page int, limit int) string {
+	return fmt.Sprintf("https://pokeapi.co/api/v2/location-area/?offset=%d&limit=%d", (page-1)*limit, limit)
+}
+
+func pageCount(count int, limit int) int {
+	return max(1, (count+limit-1)/limit)
+}
+
+// mapPageCount finds how many pages map has for a region, or for every
+// location area when region is empty.
+func (c *apiClient) mapPageCount(region string, limit int) (int, error) {
+	if len(region) > 0 {
+		response, err := c.getRegion(region)
+		if err != nil {
+			return 0, err
+		}
+		return pageCount(len(response.Locations), limit), nil
+	}
+
+	var locations LocationsResponse
+	err := c.getJsonResource(locationAreaPageUrl(1, limit), &locations)
+	if err != nil {
+		return 0, err
+	}
+	return pageCount(locations.Count, limit), nil
+}
+
+// fetchMapPage gets the lines map prints for one page along with the number
+// of pages. Without a region that's a page of location areas, with one it's
+// a page of the region's locations and the areas in each.
+func (c *apiClient) fetchMapPage(region string, page int, limit int) ([]string, int, error) {
+	// Checked before the request, which would otherwise ask for a negative
+	// offset
+	if page < 1 {
+		return nil, 0, fmt.Errorf("There's no page %d, pages start at 1", page)
+	}
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("The page size must be at least 1, not %d", limit)
+	}
+
+	if len(region) == 0 {
+		var locations LocationsResponse
+		err := c.getJsonResource(locationAreaPageUrl(page, limit), &locations)
+		if err != nil {
+			return nil, 0, err
+		}
+		pages := pageCount(locations.Count, limit)
+		if page < 1 || page > pages {
+			return nil, 0, fmt.Errorf("There are only %d pages", pages)
+		}
+
+		lines := []string{}
+		for _, loc := range locations.Results {
+			lines = append(lines, loc.Name)
+		}
+		return lines, pages, nil
+	}
+
+	response, err := c.getRegion(region)
+	if err != nil {
+		return nil, 0, err
+	}
+	pages := pageCount(len(response.Locations), limit)
+	if page < 1 || page > pages {
+		return nil, 0, fmt.Errorf("%s only has %d pages", response.Name, pages)
+	}
+
+	lines := []string{}
+	start := (page - 1) * limit
+	end := min(start+limit, len(response.Locations))
+	for _, resource := range response.Locations[start:end] {
+		location, err := c.getLocation(resource.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(location.Areas) == 0 {
+			lines = append(lines, location.Name+" (no areas)")
+			continue
+		}
+		lines = append(lines, location.Name+":")
+		for _, area := range location.Areas {
+			lines = append(lines, "\t- "+area.Name)
+		}
+	}
+	return lines, pages, nil
+}
+
+// showMapPage prints a page and remembers it, so map and mapb carry on from
+// there.
+func showMapPage(conf *config, region string, page int) error {
+	lines, pages, err := conf.api.fetchMapPage(region, page, conf.mapLimit)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		fmt.Fprintln(conf.out, line)
+	}
+	fmt.Fprintf(conf.out, "page %d/%d\n", page, pages)
+
+	conf.mapRegion = region
+	conf.mapPage = page
+	return nil
+}
+
+// showAllMapPages prints every page, one at a time as they're fetched.
+func showAllMapPages(conf *config, region string) error {
+	for page, pages := 1, 1; page <= pages; page++ {
+		lines, total, err := conf.api.fetchMapPage(region, page, conf.mapLimit)
+		if err != nil {
+			return err
+		}
+		pages = total
+
+		for _, line := range lines {
+			fmt.Fprintln(conf.out, line)
+		}
+		conf.mapRegion = region
+		conf.mapPage = page
+	}
+	return nil
+}
+
+func commandRegion(conf *config, args []string) error {
+	if len(args) == 0 {
+		var regions resourceList
+		err := conf.api.getJsonResource("https://pokeapi.co/api/v2/region/?limit=100", &regions)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(conf.out, "Regions:")
+		for _, region := range regions.Results {
+			fmt.Fprintf(conf.out, "\t- %s\n", region.Name)
+		}
+		return nil
+	}
+
+	region, err := conf.api.getRegion(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(conf.out, "Region: %s\n", region.Name)
+	if region.MainGeneration != nil {
+		fmt.Fprintf(conf.out, "Generation: %s\n", region.MainGeneration.Name)
+	}
+	fmt.Fprintf(conf.out, "Locations (%d):\n", len(region.Locations))
+	for _, location := range region.Locations {
+		fmt.Fprintf(conf.out, "\t- %s\n", location.Name)
+	}
+
+	return nil
+}
+
+func commandLocation(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Must pass a location to the location command")
+	}
+
+	location, err := conf.api.getLocation(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(conf.out, "Location: %s\n", location.Name)
+	fmt.Fprintf(conf.out, "Region: %s\n", regionName(location))
+	if len(location.Areas) == 0 {
+		fmt.Fprintln(conf.out, "No areas to explore")
+		return nil
+	}
+	fmt.Fprintln(conf.out, "Areas:")
+	for _, area := range location.Areas {
+		fmt.Fprintf(conf.out, "\t- %s\n", area.Name)
+	}
+
+	return nil
+}
+
+func commandArea(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Must pass a location area to the area command")
+	}
+
+	area, err := conf.api.getLocationArea(args[0])
+	if err != nil {
+		return err
+	}
+
+	location, err := conf.api.getLocation(area.Location.Name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(conf.out, "Area: %s\n", area.Name)
+	fmt.Fprintf(conf.out, "Location: %s\n", location.Name)
+	fmt.Fprintf(conf.out, "Region: %s\n", regionName(location))
+
+	others := []string{}
+	for _, other := range location.Areas {
+		if other.Name != area.Name {
+			others = append(others, other.Name)
+		}
+	}
+	if len(others) > 0 {
+		fmt.Fprintf(conf.out, "Other areas in %s: %s\n", location.Name, strings.Join(others, ", "))
+	}
+	fmt.Fprintf(conf.out, "%d pokemon can be found here, explore %s to see them\n", len(area.PokemonEncounters), area.Name)
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPageCount(t *testing.T) {
+	cases := []struct {
+		count    int
+		limit    int
+		expected int
+	}{
+		{count: 1054, limit: 20, expected: 53},
+		{count: 1040, limit: 20, expected: 52},
+		{count: 5, limit: 20, expected: 1},
+		{count: 0, limit: 20, expected: 1},
+	}
+
+	for _, c := range cases {
+		actual := pageCount(c.count, c.limit)
+		if actual != c.expected {
+			t.Errorf("pageCount(%d, %d): actual %d != expected %d", c.count, c.limit, actual, c.expected)
+		}
+	}
+}
+
+func TestLocationAreaPageUrl(t *testing.T) {
+	expected := "https://pokeapi.co/api/v2/location-area/?offset=40&limit=20"
+	actual := locationAreaPageUrl(3, 20)
+	if actual != expected {
+		t.Errorf("actual %s != expected %s", actual, expected)
+	}
+}
+
+func TestFetchMapPage(t *testing.T) {
+	conf, _ := newTestConfig(t, map[string]string{
+		locationAreaPageUrl(1, 2): `{"count": 3, "results": [{"name": "canalave-city-area"}, {"name": "eterna-city-area"}]}`,
+	})
+
+	lines, pages, err := conf.api.fetchMapPage("", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pages != 2 || len(lines) != 2 || lines[0] != "canalave-city-area" {
+		t.Errorf("unexpected page %v of %d", lines, pages)
+	}
+
+	for _, c := range []struct{ page, limit int }{{0, 2}, {-1, 2}, {1, 0}} {
+		if _, _, err := conf.api.fetchMapPage("", c.page, c.limit); err == nil {
+			t.Errorf("page %d limit %d: expected an error", c.page, c.limit)
+		}
+	}
+}
+
+// kantoResponses is a region with a location that has no areas, one whose
+// areas include viridian forest, and one on the next page of a two per page
+// map.
+var kantoResponses = map[string]string{
+	"https://pokeapi.co/api/v2/region/?limit=100": `{"count": 2, "results": [{"name": "kanto"}, {"name": "johto"}]}`,
+	"https://pokeapi.co/api/v2/region/kanto/": `{
+		"name": "kanto",
+		"main_generation": {"name": "generation-i"},
+		"locations": [{"name": "pallet-town"}, {"name": "viridian-forest"}, {"name": "route-2"}]
+	}`,
+	"https://pokeapi.co/api/v2/location/pallet-town/": `{"name": "pallet-town", "region": {"name": "kanto"}, "areas": []}`,
+	"https://pokeapi.co/api/v2/location/viridian-forest/": `{
+		"name": "viridian-forest",
+		"region": {"name": "kanto"},
+		"areas": [{"name": "viridian-forest-area"}, {"name": "viridian-forest-depths"}]
+	}`,
+	"https://pokeapi.co/api/v2/location/route-2/":                   `{"name": "route-2", "areas": [{"name": "route-2-area"}]}`,
+	"https://pokeapi.co/api/v2/location-area/viridian-forest-area/": viridianForestJson,
+}
+
+func TestFetchMapPageRegion(t *testing.T) {
+	conf, out := newTestConfig(t, kantoResponses)
+	conf.mapLimit = 2
+
+	lines, pages, err := conf.api.fetchMapPage("kanto", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"pallet-town (no areas)", "viridian-forest:", "\t- viridian-forest-area", "\t- viridian-forest-depths"}
+	if pages != 2 || strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("actual %q of %d != expected %q of 2", lines, pages, expected)
+	}
+
+	err = showMapPage(conf, "kanto", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "route-2:\n\t- route-2-area\npage 2/2\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	if conf.mapRegion != "kanto" || conf.mapPage != 2 {
+		t.Errorf("actual %s page %d != expected kanto page 2", conf.mapRegion, conf.mapPage)
+	}
+
+	if _, _, err := conf.api.fetchMapPage("kanto", 3, 2); err == nil {
+		t.Errorf("expected an error past the last page")
+	}
+}
+
+func TestCommandRegion(t *testing.T) {
+	conf, out := newTestConfig(t, kantoResponses)
+
+	err := commandRegion(conf, []string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "Regions:\n\t- kanto\n\t- johto\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	out.Reset()
+	err = commandRegion(conf, []string{"kanto"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{"Region: kanto\n", "Generation: generation-i\n", "Locations (3):\n", "\t- route-2\n"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in output %q", expected, out.String())
+		}
+	}
+}
+
+func TestCommandLocation(t *testing.T) {
+	conf, out := newTestConfig(t, kantoResponses)
+
+	cases := []struct {
+		location string
+		expected string
+	}{
+		{"viridian-forest", "Location: viridian-forest\nRegion: kanto\nAreas:\n\t- viridian-forest-area\n\t- viridian-forest-depths\n"},
+		{"pallet-town", "Location: pallet-town\nRegion: kanto\nNo areas to explore\n"},
+		{"route-2", "Location: route-2\nRegion: unknown region\nAreas:\n\t- route-2-area\n"},
+	}
+
+	for _, c := range cases {
+		out.Reset()
+		err := commandLocation(conf, []string{c.location})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.location, err)
+			continue
+		}
+		if out.String() != c.expected {
+			t.Errorf("%s: actual %q != expected %q", c.location, out.String(), c.expected)
+		}
+	}
+
+	if err := commandLocation(conf, []string{}); err == nil {
+		t.Errorf("expected an error without a location")
+	}
+}
+
+func TestCommandArea(t *testing.T) {
+	conf, out := newTestConfig(t, kantoResponses)
+
+	err := commandArea(conf, []string{"viridian-forest-area"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Area: viridian-forest-area\n" +
+		"Location: viridian-forest\n" +
+		"Region: kanto\n" +
+		"Other areas in viridian-forest: viridian-forest-depths\n" +
+		"2 pokemon can be found here, explore viridian-forest-area to see them\n"
+	if out.String() != expected {
+		t.Errorf("actual %q != expected %q", out.String(), expected)
+	}
+
+	if err := commandArea(conf, []string{}); err == nil {
+		t.Errorf("expected an error without an area")
+	}
+}
@@ -3,25 +3,50 @@ package main
 import (
     "fmt"
     "strings"
-    "bufio"
+    "io"
     "os"
 	"errors"
 	"time"
 	"encoding/json"
 	"math/rand"
+	"strconv"
+	"github.com/mikeheiberger/pokedexcli/internal/battle"
 	"github.com/mikeheiberger/pokedexcli/internal/pokedexapi"
 	"github.com/mikeheiberger/pokedexcli/internal/pokecache"
+	"github.com/mikeheiberger/pokedexcli/internal/repl"
 )
 
-type cliCommand struct {
-    name        string
-    description string
-    callback    func(*config, string) error
+type config struct {
+	mapPage		int
+	mapLimit	int
+	mapRegion	string
+	currentArea	string
+	settings	settings
+	battle		*activeBattle
+	// out is where commands write, so their output can be captured
+	out			io.Writer
+	// The trainer playing, their pokemon and items, and the API client the
+	// session reads through
+	profile		string
+	saveFile	string
+	pokedex		*Pokedex
+	storage		*Storage
+	inventory	*Inventory
+	api			*apiClient
 }
 
-type config struct {
-	prevUrl	string
-	nextUrl	string
+// apiClient fetches from the PokeAPI, keeping responses and assets cached.
+type apiClient struct {
+	cache		*pokecache.Cache
+	assets		*pokedexapi.AssetCache
+	typeChart	battle.TypeChart
+}
+
+func newApiClient(interval time.Duration) *apiClient {
+	return &apiClient{
+		cache:	pokecache.NewCache(interval),
+		assets:	pokedexapi.NewAssetCache(interval),
+	}
 }
 
 type LocationsResponse struct {
@@ -357,54 +382,226 @@ type Pokemon struct {
 	Weight int `json:"weight"`
 }
 
-var commands map[string]cliCommand
-
-var cache *pokecache.Cache
-
-var pokedex map[string]Pokemon
+var commands *repl.Registry[*config]
 
 func initCommands() {
-    commands = map[string]cliCommand{
-        "help" : {
-            name:           "help",
-            description:    "Displays a help message",
-            callback:       commandHelp,
+    commands = repl.NewRegistry[*config]()
+    for _, command := range []repl.Command[*config]{
+        {
+            Name:           "help",
+            Description:    "Displays a help message",
+            Callback:       commandHelp,
         },
-        "exit" : {
-            name:           "exit",
-            description:    "Exit the pokedex",
-            callback:       commandExit,
+        {
+            Name:           "exit",
+            Description:    "Exit the pokedex",
+            Callback:       commandExit,
         },
-		"map" : {
-			name:			"map",
-			description:	"Displays the next 20 locations",
-			callback:		commandMap,
-		},
-		"mapb" : {
-			name:			"mapb",
-			description:	"Displays the previous 20 locations",
-			callback:		commandMapBack,
-		},
-		"explore" : {
-			name:			"explore",
-			description:	"Displays the pokemon at a location",
-			callback:		commandExplore,
-		},
-		"catch" : {
-			name:			"catch",
-			description:	"Attempts to catch a pokemon",
-			callback:		commandCatch,
-		},
-		"inspect" : {
-			name:			"inspect",
-			description:	"Gives the name, height, weight, stats, and type(s) of a pokemon in your pokedex",
-			callback:		commandInspect,
-		},
-		"pokedex" : {
-			name:			"pokedex",
-			description:	"Displays the list of all pokemon you've caught",
-			callback:		commandPokedex,
+		{
+			Name:			"map",
+			Description:	"Displays the next page of locations: map [first|last] [--page n] [--limit n] [--all] [--region r|--region all]",
+			Callback:		commandMap,
+		},
+		{
+			Name:			"mapb",
+			Description:	"Displays the previous page of locations",
+			Callback:		commandMapBack,
+		},
+		{
+			Name:			"region",
+			Description:	"Lists the regions, or the locations in one: region [name]",
+			Callback:		commandRegion,
+		},
+		{
+			Name:			"location",
+			Description:	"Displays a location's region and areas",
+			Callback:		commandLocation,
+		},
+		{
+			Name:			"area",
+			Description:	"Displays where a location area is and what else is nearby",
+			Callback:		commandArea,
+		},
+		{
+			Name:			"where",
+			Description:	"Lists where a pokemon can be found in the wild, best odds first",
+			Callback:		commandWhere,
+		},
+		{
+			Name:			"explore",
+			Description:	"Displays the pokemon at a location",
+			Callback:		commandExplore,
+		},
+		{
+			Name:			"catch",
+			Description:	"Attempts to catch a pokemon",
+			Callback:		commandCatch,
+		},
+		{
+			Name:			"inspect",
+			Description:	"Gives the details, height, weight, stats, and type(s) of a pokemon in your pokedex: inspect <pokemon> [--sprite [--shiny] [--back] [--version v] [--color truecolor|256|ascii]]",
+			Callback:		commandInspect,
+		},
+		{
+			Name:			"info",
+			Description:	"Displays a species' types, abilities and base stats: info <pokemon> [--sprite [--shiny] [--back] [--version v] [--color truecolor|256|ascii]]",
+			Callback:		commandInfo,
+		},
+		{
+			Name:			"inventory",
+			Description:	"Displays the items in your bag",
+			Callback:		commandInventory,
+		},
+		{
+			Name:			"export",
+			Description:	"Writes your caught pokemon to a file: export csv|markdown|html <file>",
+			Callback:		commandExport,
+			KeepCase:		true,
+		},
+		{
+			Name:			"import",
+			Description:	"Merges another trainer's save into your pokedex: import <file> [--policy keep-mine|keep-theirs|keep-both] [--dry-run]",
+			Callback:		commandImport,
+			KeepCase:		true,
+		},
+		{
+			Name:			"profile",
+			Description:	"Manages trainer profiles: profile new|switch|delete <name> or profile list",
+			Callback:		commandProfile,
+		},
+		{
+			Name:			"set",
+			Description:	"Shows or changes your settings: set [page-size n|color truecolor|256|ascii|auto]",
+			Callback:		commandSet,
+		},
+		{
+			Name:			"trade",
+			Description:	"Trades pokemon with another profile: trade propose <profile> <yours> <theirs>, trade list, trade review|accept|decline <offer>",
+			Callback:		commandTrade,
+		},
+		{
+			Name:			"pokedex",
+			Description:	"Displays the pokedex: pokedex [--region r|--generation g] [--missing|--seen|--caught] [--progress], pokedex <species> for the ones you own, or search caught pokemon with pokedex [--sort id|name|caught|bst] [--reverse] [--type t] [--min-stat stat=n] [type:water bst>500 ability:swift-swim ...]",
+			Callback:		commandPokedex,
+		},
+		{
+			Name:			"compare",
+			Description:	"Compares the stats, types, abilities and moves of caught pokemon or species: compare <a> <b> [c...] [--base]",
+			Callback:		commandCompare,
+		},
+		{
+			Name:			"statcalc",
+			Description:	"Calculates the stats of a hypothetical build: statcalc <pokemon> [level=] [nature=] [ivs=] [evs=]",
+			Callback:		commandStatCalc,
+		},
+		{
+			Name:			"party",
+			Description:	"Displays the pokemon in your party",
+			Callback:		commandParty,
+		},
+		{
+			Name:			"box",
+			Description:	"Displays the pokemon in a PC box: box [number]",
+			Callback:		commandBox,
+		},
+		{
+			Name:			"deposit",
+			Description:	"Moves a pokemon from your party to the PC",
+			Callback:		commandDeposit,
+		},
+		{
+			Name:			"withdraw",
+			Description:	"Moves a pokemon from the PC to your party",
+			Callback:		commandWithdraw,
+		},
+		{
+			Name:			"swap",
+			Description:	"Swaps the places of two pokemon in your party or PC",
+			Callback:		commandSwap,
+		},
+		{
+			Name:			"type",
+			Description:	"Displays what a type is strong and weak against",
+			Callback:		commandType,
+		},
+		{
+			Name:			"move",
+			Description:	"Displays a move's details and which pokemon learn it: move <name> [--learners [--page n]]",
+			Callback:		commandMove,
+		},
+		{
+			Name:			"ability",
+			Description:	"Displays an ability's effect and which pokemon have it",
+			Callback:		commandAbility,
+		},
+		{
+			Name:			"evolution",
+			Description:	"Displays the evolution chain of a pokemon",
+			Callback:		commandEvolution,
+		},
+		{
+			Name:			"evolve",
+			Description:	"Evolves a caught pokemon if it's ready: evolve <pokemon> [item] [into=<species>]",
+			Callback:		commandEvolve,
+		},
+		{
+			Name:			"learn",
+			Description:	"Teaches a pokemon a move it learned by leveling up: learn <pokemon> <move> <move to forget>|skip",
+			Callback:		commandLearn,
+		},
+		{
+			Name:			"battle",
+			Description:	"Starts a battle: battle wild [pokemon] or battle trainer <name> <pokemon[:level]>...",
+			Callback:		commandBattle,
+		},
+		{
+			Name:			"fight",
+			Description:	"Uses a move in battle, by name or number",
+			Callback:		commandFight,
+		},
+		{
+			Name:			"switch",
+			Description:	"Switches to another party pokemon in battle",
+			Callback:		commandSwitch,
+		},
+		{
+			Name:			"run",
+			Description:	"Tries to run from a wild battle",
+			Callback:		commandRun,
+		},
+		{
+			Name:			"forfeit",
+			Description:	"Gives up the current battle",
+			Callback:		commandForfeit,
+		},
+		{
+			Name:			"rename",
+			Description:	"Gives a caught pokemon a nickname",
+			Callback:		commandRename,
+		},
+		{
+			Name:			"release",
+			Description:	"Releases a caught pokemon back into the wild",
+			Callback:		commandRelease,
+		},
+		{
+			Name:			"alias",
+			Description:	"Lists aliases or adds one: alias [name command...] [--delete name]",
+			Callback:		commandAlias,
+			KeepCase:		true,
 		},
+		{
+			Name:			"macro",
+			Description:	"Lists macros or adds one running several commands: macro [name command $1; command...] [--delete name]",
+			Callback:		commandMacro,
+			KeepCase:		true,
+		},
+    } {
+        commands.Register(command)
+    }
+
+    for alias, name := range builtinAliases {
+        commands.Alias(alias, name)
     }
 }
 
@@ -414,133 +611,261 @@ func main() {
 
     initCommands()
 
-	cache = pokecache.NewCache(interval)
 
-	pokedex = map[string]Pokemon{}
+	flags, positional, err := parseFlags(os.Args[1:], "profile", "addr")
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	profile := defaultProfile
+	if name, ok := flags["profile"]; ok {
+		profile = name
+	}
 
-	configuration := config{
-		"",
-		"https://pokeapi.co/api/v2/location-area/",
+	configuration := config{out: os.Stdout, api: newApiClient(interval)}
+	err = switchProfile(&configuration, profile, true)
+	if err != nil {
+		fmt.Printf("Could not load profile %s: %v\n", profile, err)
+		os.Exit(1)
 	}
 
-    scanner := bufio.NewScanner(os.Stdin)
-    for {
-        fmt.Print("Pokedex > ")
-        scanner.Scan()
-        input := cleanInput(scanner.Text())
+	if len(positional) > 0 && positional[0] == "serve" {
+		addr := ":8080"
+		if value, ok := flags["addr"]; ok {
+			addr = value
+		}
+		err = serve(&configuration, addr)
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
 
-        if command, ok := commands[input[0]]; ok {
-			param := ""
-			if len(input) > 1 {
-				param = input[1]
-			}
+	if flags["tui"] == "true" {
+		err = runTui(&configuration)
+		unlockProfile(configuration.profile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
 
-            err := command.callback(&configuration, param)
-            if err != nil {
-                fmt.Println(err.Error())
-            }
-        } else {
-            fmt.Println("Unknown command")
-        }
-    }
+	session := newSession(&configuration, os.Stdin, os.Stdout)
+	err = session.Run()
+	unlockProfile(configuration.profile)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
 }
 
-func cleanInput(text string) []string {
-    split := strings.Fields(strings.ToLower(text))
-    return split
+// parseFlags separates --flag arguments from positional ones. Flags named in
+// valued take the next argument as their value, others are set to "true".
+func parseFlags(args []string, valued ...string) (map[string]string, []string, error) {
+	flags := map[string]string{}
+	positional := []string{}
+	for i := 0; i < len(args); i++ {
+		name, ok := strings.CutPrefix(args[i], "--")
+		if !ok {
+			positional = append(positional, args[i])
+			continue
+		}
+
+		if name, value, ok := strings.Cut(name, "="); ok {
+			flags[name] = value
+			continue
+		}
+
+		flags[name] = "true"
+		for _, valuedName := range valued {
+			if name != valuedName {
+				continue
+			}
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("--%s needs a value", name)
+			}
+			flags[name] = args[i+1]
+			i++
+		}
+	}
+	return flags, positional, nil
 }
 
-func commandExit(conf *config, param string) error {
-    fmt.Println("Closing the Pokedex... Goodbye!")
-    os.Exit(0)
-    return nil
+// newSession runs the registered commands against conf, with their output
+// going to out. Battles only allow the battle commands, and the game is saved
+// after every command that works.
+func newSession(conf *config, in io.Reader, out io.Writer) *repl.Session[*config] {
+	conf.out = out
+	return &repl.Session[*config]{
+		Registry:	commands,
+		State:		conf,
+		In:			in,
+		Out:		out,
+		Prompt:		"Pokedex > ",
+		Before:		func(command repl.Command[*config]) error {
+			if conf.battle != nil && !battleCommands[command.Name] {
+				return errors.New("You're in a battle! Use fight, switch, run or forfeit")
+			}
+			return nil
+		},
+		After:		func(repl.Command[*config]) error {
+			err := writeSave(conf.saveFile, conf)
+			if err != nil {
+				return fmt.Errorf("Could not save: %v", err)
+			}
+			return nil
+		},
+		Aliases:	func() map[string]string {
+			return conf.settings.Aliases
+		},
+		Macros:		func() map[string]string {
+			return conf.settings.Macros
+		},
+	}
 }
 
-func commandHelp(conf *config, param string) error {
-    fmt.Println("Welcome to the Pokedex!")
-    fmt.Print("Usage:\n\n")
-    for _, value := range commands {
-        fmt.Printf("%s: %s\n", value.name, value.description)
+func commandExit(conf *config, args []string) error {
+    fmt.Fprintln(conf.out, "Closing the Pokedex... Goodbye!")
+    return repl.ErrExit
+}
+
+func commandHelp(conf *config, args []string) error {
+    fmt.Fprintln(conf.out, "Welcome to the Pokedex!")
+    fmt.Fprint(conf.out, "Usage:\n\n")
+    for _, value := range commands.Commands() {
+        fmt.Fprintf(conf.out, "%s: %s\n", value.Name, value.Description)
     }
     return nil
 }
 
-func commandMap(conf *config, param string) error {
-	jsonData, err := getJsonFromCacheOrServer(conf.nextUrl)
+func commandMap(conf *config, args []string) error {
+	flags, positional, err := parseFlags(args, "region", "page", "limit")
 	if err != nil {
 		return err
 	}
 
-	err = printLocationData(conf, jsonData)
-	return err
-}
+	region := conf.mapRegion
+	if name, ok := flags["region"]; ok {
+		region = name
+		if name == "all" {
+			region = ""
+		}
+	}
 
-func commandMapBack(conf *config, param string) error {
-	if len(conf.prevUrl) == 0 {
-		return errors.New("you're on the first page")
+	_, limitChanged := flags["limit"]
+	if limitChanged {
+		limit, err := strconv.Atoi(flags["limit"])
+		if err != nil || limit < 1 {
+			return fmt.Errorf("--limit needs a positive number, got %s", flags["limit"])
+		}
+		conf.mapLimit = limit
 	}
 
-	jsonData, err := getJsonFromCacheOrServer(conf.prevUrl)
-	if err != nil {
-		return err
+	if flags["all"] == "true" {
+		return showAllMapPages(conf, region)
+	}
+
+	page := conf.mapPage + 1
+	if region != conf.mapRegion || limitChanged {
+		page = 1
+	}
+	if value, ok := flags["page"]; ok {
+		page, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("--page needs a number, got %s", value)
+		}
+	}
+	if len(positional) > 0 {
+		switch positional[0] {
+		case "first":
+			page = 1
+		case "last":
+			page, err = conf.api.mapPageCount(region, conf.mapLimit)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Unknown map page %s, use first or last", positional[0])
+		}
 	}
 
-	err = printLocationData(conf, jsonData)
-	return err
+	return showMapPage(conf, region, page)
 }
 
-func commandExplore(conf *config, param string) error {
-	const baseUrl = "https://pokeapi.co/api/v2/location-area/"
+func commandMapBack(conf *config, args []string) error {
+	if conf.mapPage <= 1 {
+		return errors.New("you're on the first page")
+	}
+
+	return showMapPage(conf, conf.mapRegion, conf.mapPage-1)
+}
 
-	if len(param) == 0 {
+func commandExplore(conf *config, args []string) error {
+	if len(args) == 0 {
 		return errors.New("Must pass a location to the explore command")
 	}
 
-	fullUrl := baseUrl + param + "/"
-	jsonData, err := getJsonFromCacheOrServer(fullUrl)
+	explore, err := conf.api.getLocationArea(args[0])
 	if err != nil {
 		return err
 	}
 
-	var explore ExploreResponse
-	err = json.Unmarshal(jsonData, &explore)
-	if err != nil {
-		return fmt.Errorf("Unmarshal failed: %v", err)
-	}
+	conf.currentArea = explore.Name
 
 	if len(explore.PokemonEncounters) == 0 {
-		fmt.Println("No pokemon in the area!")
+		fmt.Fprintln(conf.out, "No pokemon in the area!")
 		return nil
 	}
 
-	fmt.Println("Found Pokemon:")
+	fmt.Fprintln(conf.out, "Found Pokemon:")
 	for _, encounter := range explore.PokemonEncounters {
-		fmt.Printf("- %s\n", encounter.Pokemon.Name)
+		fmt.Fprintf(conf.out, "- %s\n", encounter.Pokemon.Name)
+		conf.pokedex.MarkSeen(conf.api.speciesOf(encounter.Pokemon.Name))
 	}
 
 	return nil
 }
 
-func commandCatch (conf *config, param string) error {
-	const baseUrl = "https://pokeapi.co/api/v2/pokemon/"
-
-	if len(param) == 0 {
+func commandCatch (conf *config, args []string) error {
+	if len(args) == 0 {
 		return errors.New("Must pass a pokemon to the catch command")
 	}
+	param := args[0]
 
-	fmt.Printf("Throwing a Pokeball at %s...\n", param)
+	fmt.Fprintf(conf.out, "Throwing a Pokeball at %s...\n", param)
 
-	fullUrl := baseUrl + param + "/"
-	jsonData, err := getJsonFromCacheOrServer(fullUrl)
+	pokemon, caught, err := throwPokeball(conf, param)
 	if err != nil {
 		return err
 	}
 
-	var pokemon Pokemon
-	err = json.Unmarshal(jsonData, &pokemon)
+	if caught == nil {
+		fmt.Fprintf(conf.out, "%s escaped!\n", pokemon.Name)
+		return nil
+	}
+
+	fmt.Fprintf(conf.out, "%s was caught!\n", pokemon.Name)
+	fmt.Fprintf(conf.out, "Added to your pokedex as %s\n", caught)
+	inParty, err := storeCaught(conf, pokemon, caught)
+	if inParty {
+		fmt.Fprintf(conf.out, "%s joined your party\n", caught.Name())
+	} else {
+		fmt.Fprintf(conf.out, "Your party is full, %s was sent to the PC\n", caught.Name())
+	}
+	if err != nil {
+		fmt.Fprintln(conf.out, err.Error())
+	}
+
+	return nil
+}
+
+// throwPokeball tries to catch a pokemon, adding it to the pokedex when it
+// works. The caught pokemon is nil if it escaped.
+func throwPokeball(conf *config, name string) (Pokemon, *CaughtPokemon, error) {
+	pokemon, err := conf.api.getPokemon(name)
 	if err != nil {
-		return fmt.Errorf("Unmarshal failed: %v", err)
+		return pokemon, nil, err
 	}
+	conf.pokedex.MarkSeen(pokemon.Species.Name)
 
 	var chance int
 	if pokemon.BaseExperience < 50 {
@@ -554,56 +879,172 @@ func commandCatch (conf *config, param string) error {
 	}
 
 	roll := rand.Intn(100)
-	if roll >= chance {
-		fmt.Printf("%s was caught!\n", pokemon.Name)
-		pokedex[pokemon.Name] = pokemon
-	} else {
-		fmt.Printf("%s escaped!\n", pokemon.Name)
+	if roll < chance {
+		return pokemon, nil, nil
 	}
 
-	return nil
+	caught, err := newCaughtPokemon(conf, pokemon)
+	if err != nil {
+		return pokemon, nil, err
+	}
+
+	conf.pokedex.Add(caught)
+	conf.pokedex.MarkCaught(pokemon.Species.Name, caught.CaughtAt)
+	return pokemon, caught, nil
+}
+
+// storeCaught puts a newly caught pokemon in the party or PC, reporting
+// whether it went to the party. The pokemon is stored even when giving the
+// lead of the party experience fails, which is the error returned.
+func storeCaught(conf *config, pokemon Pokemon, caught *CaughtPokemon) (bool, error) {
+	var lead *CaughtPokemon
+	if len(conf.storage.Party) > 0 {
+		lead = conf.pokedex.Caught[conf.storage.Party[0]]
+	}
+	inParty := conf.storage.Store(caught.ID)
+
+	// Catching a pokemon gives the lead of the party experience for it
+	if lead != nil {
+		_, err := gainExperience(conf, lead, defeatExperience(pokemon.BaseExperience, caught.Level, false, 1))
+		if err != nil {
+			return inParty, fmt.Errorf("%s didn't get experience for the catch: %v", lead.Name(), err)
+		}
+	}
+	return inParty, nil
 }
 
-func commandInspect(conf *config, param string) error {
-	if len(param) == 0 {
+func commandInspect(conf *config, args []string) error {
+	flags, positional, err := parseFlags(args, "version", "color")
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
 		return errors.New("Must pass a pokemon to the inspect command")
 	}
 
-	pokemon, ok := pokedex[param]
-	if !ok {
-		return errors.New("You have not caught that pokemon")
+	caught, err := conf.pokedex.Find(positional[0])
+	if err != nil {
+		return err
+	}
+
+	details, err := conf.api.newPokemonDetails(caught)
+	if err != nil {
+		return err
+	}
+	pokemon := details.pokemon
+
+	if flags["sprite"] == "true" {
+		opts, err := spriteOptionsFromFlags(conf, flags)
+		if err != nil {
+			return err
+		}
+		err = printSprite(conf, pokemon, details.spriteOptions(opts))
+		if err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("Name: %s\n", pokemon.Name)
-	fmt.Printf("Height: %d\n", pokemon.Height)
-	fmt.Printf("Weight: %d\n", pokemon.Weight)
-	fmt.Println("Stats:")
+	fmt.Fprintf(conf.out, "Name: %s\n", caught.Name())
+	fmt.Fprintf(conf.out, "ID: #%d\n", caught.ID)
+	fmt.Fprintf(conf.out, "Species: %s\n", pokemon.Name)
+	fmt.Fprintf(conf.out, "Level: %d\n", caught.Level)
+	fmt.Fprintf(conf.out, "Experience: %s\n", conf.api.experienceToNext(caught, pokemon))
+	fmt.Fprintf(conf.out, "Friendship: %d\n", caught.Friendship)
+	fmt.Fprintf(conf.out, "Nature: %s\n", natureSummary(caught.Nature))
+	fmt.Fprintf(conf.out, "Gender: %s\n", caught.Gender)
+	if len(caught.Ability) > 0 {
+		fmt.Fprintf(conf.out, "Ability: %s\n", caught.Ability)
+	}
+	fmt.Fprintf(conf.out, "Species abilities: %s\n", speciesAbilities(pokemon))
+	if caught.Shiny {
+		fmt.Fprintln(conf.out, "Shiny: yes")
+	}
+	fmt.Fprintf(conf.out, "Caught: %s", caught.CaughtAt.Format("2006-01-02 15:04"))
+	if len(caught.Location) > 0 {
+		fmt.Fprintf(conf.out, " at %s", caught.Location)
+	}
+	fmt.Fprintln(conf.out, )
+	fmt.Fprintf(conf.out, "Height: %d\n", pokemon.Height)
+	fmt.Fprintf(conf.out, "Weight: %d\n", pokemon.Weight)
+	fmt.Fprintln(conf.out, "Stats:")
 	for _, stat := range pokemon.Stats {
-		fmt.Printf("\t-%s: %d\n", stat.Stat.Name, stat.BaseStat)
+		name := stat.Stat.Name
+		fmt.Fprintf(conf.out, "\t-%s: %d (base %d, IV %d, EV %d)\n", name, details.stats[name], stat.BaseStat, caught.IVs[name], caught.EVs[name])
 	}
-	fmt.Println("Types:")
-	for _, poketype := range pokemon.Types {
-		fmt.Printf("\t- %s\n", poketype.Type.Name)
+	fmt.Fprintf(conf.out, "EV yield: %s\n", evYield(pokemon))
+	fmt.Fprintln(conf.out, "Moves:")
+	for _, move := range caught.Moves {
+		fmt.Fprintf(conf.out, "\t- %s\n", move)
+	}
+	if len(caught.PendingMoves) > 0 {
+		fmt.Fprintf(conf.out, "Wants to learn: %s\n", strings.Join(caught.PendingMoves, ", "))
+	}
+	fmt.Fprintln(conf.out, "Types:")
+	for _, poketype := range details.types {
+		fmt.Fprintf(conf.out, "\t- %s\n", poketype)
 	}
 
+	chart, err := conf.api.loadFullTypeChart()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(conf.out, "Damage taken:")
+	printMatchups(conf.out, defensiveMatchups(chart, details.types))
+
 	return nil
 }
 
-func commandPokedex(conf *config, param string) error {
-	if len(pokedex) == 0 {
+func commandPokedex(conf *config, args []string) error {
+	flags, positional, err := parseFlags(args, "region", "generation", "sort", "type", "min-stat")
+	if err != nil {
+		return err
+	}
+
+	hasTerms := false
+	for _, arg := range positional {
+		hasTerms = hasTerms || isQueryTerm(arg)
+	}
+	_, sorted := flags["sort"]
+	_, typed := flags["type"]
+	_, minStat := flags["min-stat"]
+	if hasTerms || sorted || typed || minStat {
+		scope, _, err := conf.api.dexScope(flags)
+		if err != nil {
+			return err
+		}
+		return showQuery(conf, scope, positional, flags)
+	}
+
+	if len(positional) == 0 {
+		return showDex(conf, flags)
+	}
+
+	if len(conf.pokedex.Caught) == 0 {
 		return errors.New("You haven't caught any Pokemon!")
 	}
 
-	fmt.Println("Your Pokedex:")
-	for key, _ := range pokedex {
-		fmt.Printf("\t- %s\n", key)
+	species := positional[0]
+	list := []*CaughtPokemon{}
+	for _, caught := range conf.pokedex.Sorted() {
+		if caught.isSpecies(species) {
+			list = append(list, caught)
+		}
+	}
+
+	if len(list) == 0 {
+		return fmt.Errorf("You haven't caught any %s", species)
+	}
+
+	fmt.Fprintf(conf.out, "Your %s:\n", species)
+	for _, caught := range list {
+		fmt.Fprintf(conf.out, "\t- %s\n", caught)
 	}
 
 	return nil
 }
 
-func getJsonFromCacheOrServer(url string) ([]byte, error) {
-	jsonData, ok := cache.Get(url)
+func (c *apiClient) getJsonFromCacheOrServer(url string) ([]byte, error) {
+	jsonData, ok := c.cache.Get(url)
 	if !ok {
 		var err error
 		jsonData, err = pokedexapi.QueryPokedexApi(url)
@@ -611,25 +1052,25 @@ func getJsonFromCacheOrServer(url string) ([]byte, error) {
 			return nil, err
 		}
 
-		cache.Add(url, jsonData)
+		c.cache.Add(url, jsonData)
 	}
 
 	return jsonData, nil
 }
 
-func printLocationData(conf *config, jsonData []byte) error {
-	var locations LocationsResponse
-	err := json.Unmarshal(jsonData, &locations)
+func (c *apiClient) getLocationArea(name string) (ExploreResponse, error) {
+	const baseUrl = "https://pokeapi.co/api/v2/location-area/"
+
+	var explore ExploreResponse
+	jsonData, err := c.getJsonFromCacheOrServer(baseUrl + name + "/")
 	if err != nil {
-		return fmt.Errorf("Unmarshal failed: %v", err)
+		return explore, err
 	}
 
-	conf.nextUrl = locations.Next
-	conf.prevUrl = locations.Prev
-
-	for _, loc := range locations.Results {
-		fmt.Println(loc.Name)
+	err = json.Unmarshal(jsonData, &explore)
+	if err != nil {
+		return explore, fmt.Errorf("Unmarshal failed: %v", err)
 	}
 
-	return nil
+	return explore, nil
 }
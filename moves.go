@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mikeheiberger/pokedexcli/internal/battle"
+)
+
+type MoveResponse struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Accuracy int    `json:"accuracy"`
+	Power    int    `json:"power"`
+	PP       int    `json:"pp"`
+	Priority int    `json:"priority"`
+	Type     struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"type"`
+	DamageClass struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"damage_class"`
+	EffectChance  int `json:"effect_chance"`
+	EffectEntries []struct {
+		Effect      string        `json:"effect"`
+		ShortEffect string        `json:"short_effect"`
+		Language    namedResource `json:"language"`
+	} `json:"effect_entries"`
+	LearnedByPokemon []namedResource `json:"learned_by_pokemon"`
+}
+
+const maxMoves = 4
+
+func (c *apiClient) getMove(name string) (MoveResponse, error) {
+	const baseUrl = "https://pokeapi.co/api/v2/move/"
+
+	var move MoveResponse
+	jsonData, err := c.getJsonFromCacheOrServer(baseUrl + name + "/")
+	if err != nil {
+		return move, err
+	}
+
+	err = json.Unmarshal(jsonData, &move)
+	if err != nil {
+		return move, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+
+	return move, nil
+}
+
+func (m MoveResponse) battleMove() battle.Move {
+	return battle.Move{
+		Name:     m.Name,
+		Type:     m.Type.Name,
+		Class:    m.DamageClass.Name,
+		Power:    m.Power,
+		Accuracy: m.Accuracy,
+		PP:       m.PP,
+		Priority: m.Priority,
+	}
+}
+
+// levelUpLearnset maps each move a pokemon learns by leveling up to the level
+// it's learned at. Learnsets change between games so only the most recent
+// version group the pokemon appears in is used.
+func levelUpLearnset(pokemon Pokemon) map[string]int {
+	latest := 0
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name == "level-up" {
+				latest = max(latest, resourceID(detail.VersionGroup.URL))
+			}
+		}
+	}
+
+	learnset := map[string]int{}
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name == "level-up" && resourceID(detail.VersionGroup.URL) == latest {
+				learnset[move.Move.Name] = detail.LevelLearnedAt
+			}
+		}
+	}
+
+	return learnset
+}
+
+// movesLearnedAt lists the moves a pokemon learns on reaching exactly level.
+func movesLearnedAt(pokemon Pokemon, level int) []string {
+	names := []string{}
+	for name, learnedAt := range levelUpLearnset(pokemon) {
+		if learnedAt == level {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// levelUpMoves returns the moves a pokemon learns by leveling up at or below
+// level, ordered by the level each is learned at.
+func levelUpMoves(pokemon Pokemon, level int) []string {
+	learnedAt := levelUpLearnset(pokemon)
+
+	names := make([]string, 0, len(learnedAt))
+	for name, at := range learnedAt {
+		if at <= level {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if learnedAt[names[i]] != learnedAt[names[j]] {
+			return learnedAt[names[i]] < learnedAt[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// defaultMoves is what a pokemon found at level knows: the last four moves it
+// learned by leveling up, the same as in the games.
+func defaultMoves(pokemon Pokemon, level int) []string {
+	moves := levelUpMoves(pokemon, level)
+	if len(moves) > maxMoves {
+		moves = moves[len(moves)-maxMoves:]
+	}
+	return moves
+}
+
+// effectText is the English effect description with the effect chance
+// filled in.
+func (m MoveResponse) effectText() string {
+	for _, entry := range m.EffectEntries {
+		if entry.Language.Name == "en" {
+			text := strings.Join(strings.Fields(entry.Effect), " ")
+			return strings.ReplaceAll(text, "$effect_chance", strconv.Itoa(m.EffectChance))
+		}
+	}
+	return "No effect description"
+}
+
+// learnMethods describes every way a pokemon can learn a move, e.g.
+// "level-up (lv 1, 26)", "machine".
+func learnMethods(pokemon Pokemon, moveName string) []string {
+	methods := []string{}
+	levels := map[string][]int{}
+	for _, move := range pokemon.Moves {
+		if move.Move.Name != moveName {
+			continue
+		}
+		for _, detail := range move.VersionGroupDetails {
+			method := detail.MoveLearnMethod.Name
+			if _, ok := levels[method]; !ok {
+				methods = append(methods, method)
+				levels[method] = []int{}
+			}
+			if method == "level-up" && !containsInt(levels[method], detail.LevelLearnedAt) {
+				levels[method] = append(levels[method], detail.LevelLearnedAt)
+			}
+		}
+	}
+
+	sort.Strings(methods)
+	for i, method := range methods {
+		if len(levels[method]) == 0 {
+			continue
+		}
+		sort.Ints(levels[method])
+		nums := []string{}
+		for _, level := range levels[method] {
+			nums = append(nums, strconv.Itoa(level))
+		}
+		methods[i] = fmt.Sprintf("%s (lv %s)", method, strings.Join(nums, ", "))
+	}
+
+	return methods
+}
+
+func containsInt(list []int, value int) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func valueOrDash(value int) string {
+	if value == 0 {
+		return "-"
+	}
+	return strconv.Itoa(value)
+}
+
+// learnerPageSize caps how many learners are looked up at once, since each
+// one is a request of its own.
+const learnerPageSize = 20
+
+func commandMove(conf *config, args []string) error {
+	flags, positional, err := parseFlags(args, "page")
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("Usage: move <name> [--learners [--page n]]")
+	}
+
+	move, err := conf.api.getMove(positional[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(conf.out, "Move: %s\n", move.Name)
+	fmt.Fprintf(conf.out, "Type: %s\n", move.Type.Name)
+	fmt.Fprintf(conf.out, "Category: %s\n", move.DamageClass.Name)
+	fmt.Fprintf(conf.out, "Power: %s\n", valueOrDash(move.Power))
+	fmt.Fprintf(conf.out, "Accuracy: %s\n", valueOrDash(move.Accuracy))
+	fmt.Fprintf(conf.out, "PP: %d\n", move.PP)
+	fmt.Fprintf(conf.out, "Priority: %d\n", move.Priority)
+	fmt.Fprintf(conf.out, "Effect: %s\n", move.effectText())
+
+	if flags["learners"] != "true" {
+		fmt.Fprintf(conf.out, "Learned by %d pokemon (use move %s --learners to see how)\n", len(move.LearnedByPokemon), move.Name)
+		return nil
+	}
+
+	pages := max(1, (len(move.LearnedByPokemon)+learnerPageSize-1)/learnerPageSize)
+	page := 1
+	if value, ok := flags["page"]; ok {
+		page, err = strconv.Atoi(value)
+		if err != nil || page < 1 || page > pages {
+			return fmt.Errorf("Invalid page %s, there are %d pages of learners", value, pages)
+		}
+	}
+	start := (page - 1) * learnerPageSize
+	end := min(start+learnerPageSize, len(move.LearnedByPokemon))
+
+	// A learner that can't be looked up is noted and skipped, so one bad
+	// response doesn't hide the rest
+	fmt.Fprintf(conf.out, "Learned by %d pokemon (page %d/%d):\n", len(move.LearnedByPokemon), page, pages)
+	for _, learner := range move.LearnedByPokemon[start:end] {
+		pokemon, err := conf.api.getPokemon(learner.Name)
+		if err != nil {
+			fmt.Fprintf(conf.out, "\t- %s: couldn't look up how (%v)\n", learner.Name, err)
+			continue
+		}
+		fmt.Fprintf(conf.out, "\t- %s: %s\n", pokemon.Name, strings.Join(learnMethods(pokemon, move.Name), ", "))
+	}
+	if page < pages {
+		fmt.Fprintf(conf.out, "Use move %s --learners --page %d for more\n", move.Name, page+1)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEffectText(t *testing.T) {
+	cases := []struct {
+		move     string
+		expected string
+	}{
+		{
+			move: `{"effect_chance": 10, "effect_entries": [
+				{"effect": "Has a   $effect_chance% chance\nto burn.", "language": {"name": "en"}}
+			]}`,
+			expected: "Has a 10% chance to burn.",
+		},
+		{
+			move: `{"effect_entries": [
+				{"effect": "Hat eine Chance.", "language": {"name": "de"}},
+				{"effect": "Inflicts regular damage.", "language": {"name": "en"}}
+			]}`,
+			expected: "Inflicts regular damage.",
+		},
+		{
+			move:     `{"effect_entries": [{"effect": "Hat eine Chance.", "language": {"name": "de"}}]}`,
+			expected: "No effect description",
+		},
+	}
+
+	for _, c := range cases {
+		var move MoveResponse
+		err := json.Unmarshal([]byte(c.move), &move)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		actual := move.effectText()
+		if actual != c.expected {
+			t.Errorf("actual %q != expected %q", actual, c.expected)
+		}
+	}
+}
+
+func TestLearnMethods(t *testing.T) {
+	var pokemon Pokemon
+	err := json.Unmarshal([]byte(`{"moves": [
+		{"move": {"name": "thunderbolt"}, "version_group_details": [
+			{"level_learned_at": 26, "move_learn_method": {"name": "level-up"}, "version_group": {"url": "https://pokeapi.co/api/v2/version-group/1/"}},
+			{"level_learned_at": 0, "move_learn_method": {"name": "machine"}, "version_group": {"url": "https://pokeapi.co/api/v2/version-group/1/"}},
+			{"level_learned_at": 1, "move_learn_method": {"name": "level-up"}, "version_group": {"url": "https://pokeapi.co/api/v2/version-group/2/"}},
+			{"level_learned_at": 26, "move_learn_method": {"name": "level-up"}, "version_group": {"url": "https://pokeapi.co/api/v2/version-group/3/"}},
+			{"level_learned_at": 0, "move_learn_method": {"name": "machine"}, "version_group": {"url": "https://pokeapi.co/api/v2/version-group/3/"}}
+		]},
+		{"move": {"name": "surf"}, "version_group_details": [
+			{"level_learned_at": 0, "move_learn_method": {"name": "tutor"}, "version_group": {"url": "https://pokeapi.co/api/v2/version-group/1/"}}
+		]}
+	]}`), &pokemon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		move     string
+		expected []string
+	}{
+		{"thunderbolt", []string{"level-up (lv 1, 26)", "machine"}},
+		{"surf", []string{"tutor"}},
+		{"tackle", []string{}},
+	}
+
+	for _, c := range cases {
+		actual := learnMethods(pokemon, c.move)
+		if strings.Join(actual, "|") != strings.Join(c.expected, "|") {
+			t.Errorf("%s: actual %q != expected %q", c.move, actual, c.expected)
+		}
+	}
+}
+
+func TestMoveLearners(t *testing.T) {
+	learners := []string{}
+	for i := 1; i <= learnerPageSize+1; i++ {
+		learners = append(learners, fmt.Sprintf(`{"name": "mon%d"}`, i))
+	}
+	responses := map[string]string{
+		"https://pokeapi.co/api/v2/move/zap/": `{"name": "zap", "learned_by_pokemon": [` + strings.Join(learners, ",") + `]}`,
+		// mon1 can't be read and is skipped
+		"https://pokeapi.co/api/v2/pokemon/mon1/": "not json",
+	}
+	for i := 2; i <= learnerPageSize+1; i++ {
+		responses[fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/mon%d/", i)] = fmt.Sprintf(`{"name": "mon%d", "moves": [
+			{"move": {"name": "zap"}, "version_group_details": [{"move_learn_method": {"name": "tutor"}, "version_group": {"url": "https://pokeapi.co/api/v2/version-group/1/"}}]}
+		]}`, i)
+	}
+	conf, out := newTestConfig(t, responses)
+
+	err := commandMove(conf, []string{"zap", "--learners"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output := out.String()
+	if !strings.Contains(output, "page 1/2") || !strings.Contains(output, "mon1: couldn't look up how") || !strings.Contains(output, "mon2: tutor") {
+		t.Errorf("unexpected first page:\n%s", output)
+	}
+	if strings.Contains(output, fmt.Sprintf("mon%d:", learnerPageSize+1)) || !strings.Contains(output, "--page 2") {
+		t.Errorf("expected the last learner on the next page:\n%s", output)
+	}
+
+	out.Reset()
+	err = commandMove(conf, []string{"zap", "--learners", "--page", "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), fmt.Sprintf("mon%d: tutor", learnerPageSize+1)) || strings.Contains(out.String(), "mon2:") {
+		t.Errorf("unexpected second page:\n%s", out.String())
+	}
+
+	if err := commandMove(conf, []string{"zap", "--learners", "--page", "3"}); err == nil {
+		t.Errorf("expected an error for a page past the end")
+	}
+}
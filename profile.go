@@ -0,0 +1,241 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// defaultProfile keeps using the save file from before there were profiles.
+const defaultProfile = "default"
+
+func profilePath(name string) string {
+	if name == defaultProfile {
+		return defaultSavePath()
+	}
+	return filepath.Join(filepath.Dir(defaultSavePath()), "profiles", name+".json")
+}
+
+func profileExists(name string) bool {
+	_, err := os.Stat(profilePath(name))
+	return err == nil
+}
+
+func validProfileName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// lockPath holds the process ID of the pokedex using a profile, so two can't
+// change the same save at once.
+func lockPath(name string) string {
+	return profilePath(name) + ".lock"
+}
+
+// lockProfile claims a profile for this process. A lock left behind by a
+// pokedex that's no longer running is taken over.
+func lockProfile(name string) error {
+	err := os.MkdirAll(filepath.Dir(lockPath(name)), 0755)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(lockPath(name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = fmt.Fprintf(file, "%d", os.Getpid())
+			file.Close()
+			return err
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+
+		pid, running := lockHolder(name)
+		if pid == os.Getpid() {
+			return nil
+		}
+		if running {
+			return fmt.Errorf("Profile %s is in use by another pokedex", name)
+		}
+		os.Remove(lockPath(name))
+	}
+	return fmt.Errorf("Could not lock profile %s", name)
+}
+
+// lockHolder reads which process holds a profile's lock and whether it's
+// still running.
+func lockHolder(name string) (int, bool) {
+	data, err := os.ReadFile(lockPath(name))
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return pid, false
+	}
+	return pid, process.Signal(syscall.Signal(0)) == nil
+}
+
+// unlockProfile releases a profile this process has locked.
+func unlockProfile(name string) {
+	if pid, _ := lockHolder(name); pid == os.Getpid() {
+		os.Remove(lockPath(name))
+	}
+}
+
+// profileNames lists every profile with a save file, plus the current one
+// even if it hasn't been saved yet.
+func profileNames(conf *config) []string {
+	names := []string{conf.profile}
+	if conf.profile != defaultProfile && profileExists(defaultProfile) {
+		names = append(names, defaultProfile)
+	}
+
+	paths, _ := filepath.Glob(profilePath("*"))
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		if name != conf.profile && validProfileName(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// switchProfile loads a profile's save, replacing the pokedex, storage,
+// inventory, location and settings. Unless create is set the profile must
+// exist. The profile is locked while it's in use.
+func switchProfile(conf *config, name string, create bool) error {
+	if !validProfileName(name) {
+		return fmt.Errorf("%s isn't a valid profile name, use letters, numbers, - and _", name)
+	}
+	if !create && !profileExists(name) {
+		return fmt.Errorf("There's no profile named %s, create it with profile new %s", name, name)
+	}
+
+	err := lockProfile(name)
+	if err != nil {
+		return err
+	}
+
+	// Load into a fresh config so a save that can't be read leaves the
+	// current profile as it was
+	next := config{out: conf.out, api: conf.api}
+	err = loadSave(profilePath(name), &next)
+	if err != nil {
+		if name != conf.profile {
+			unlockProfile(name)
+		}
+		return err
+	}
+
+	if len(conf.profile) > 0 && name != conf.profile {
+		unlockProfile(conf.profile)
+	}
+	next.profile = name
+	next.saveFile = profilePath(name)
+	*conf = next
+	return nil
+}
+
+func commandProfile(conf *config, args []string) error {
+	flags, positional, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("Usage: profile new|switch|list|delete [name]")
+	}
+
+	if positional[0] == "list" {
+		for _, name := range profileNames(conf) {
+			marker := ""
+			if name == conf.profile {
+				marker = " *"
+			}
+			fmt.Fprintf(conf.out, "\t- %s%s\n", name, marker)
+		}
+		return nil
+	}
+
+	if len(positional) != 2 {
+		return fmt.Errorf("Must pass a profile name to profile %s", positional[0])
+	}
+	name := positional[1]
+
+	switch positional[0] {
+	case "new":
+		if profileExists(name) || name == conf.profile {
+			return fmt.Errorf("There's already a profile named %s", name)
+		}
+		err = writeSave(conf.saveFile, conf)
+		if err != nil {
+			return err
+		}
+		err = switchProfile(conf, name, true)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(conf.out, "Created profile %s\n", name)
+	case "switch":
+		if name == conf.profile {
+			return fmt.Errorf("You're already using %s", name)
+		}
+		err = writeSave(conf.saveFile, conf)
+		if err != nil {
+			return err
+		}
+		err = switchProfile(conf, name, false)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(conf.out, "Switched to %s, %d pokemon caught\n", name, len(conf.pokedex.Caught))
+	case "delete":
+		if name == conf.profile {
+			return errors.New("You can't delete the profile you're using")
+		}
+		if name == defaultProfile {
+			return errors.New("The default profile can't be deleted")
+		}
+		if !profileExists(name) {
+			return fmt.Errorf("There's no profile named %s", name)
+		}
+		if _, running := lockHolder(name); running {
+			return fmt.Errorf("Profile %s is in use by another pokedex", name)
+		}
+		if flags["yes"] != "true" {
+			save, err := readSave(profilePath(name))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(conf.out, "This deletes %s and its %d pokemon, run profile delete %s --yes to confirm\n", name, len(save.Pokedex.Caught), name)
+			return nil
+		}
+		err = os.Remove(profilePath(name))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(conf.out, "Deleted profile %s\n", name)
+	default:
+		return fmt.Errorf("Unknown profile command %s, use new, switch, list or delete", positional[0])
+	}
+
+	return nil
+}
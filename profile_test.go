@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestSwitchProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	conf, _ := newTestConfig(t, nil)
+	conf.saveFile = profilePath(defaultProfile)
+	conf.inventory.Add("moon-stone", 1)
+
+	err := commandProfile(conf, []string{"new", "misty"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conf.profile != "misty" || conf.inventory.Count("moon-stone") != 0 {
+		t.Errorf("expected misty to start with a new bag, got %v", conf.inventory.Items)
+	}
+	if conf.mapLimit != locationPageSize {
+		t.Errorf("actual page size %d != expected %d", conf.mapLimit, locationPageSize)
+	}
+
+	err = commandProfile(conf, []string{"switch", "default"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conf.inventory.Count("moon-stone") != 1 {
+		t.Errorf("expected the default profile to keep its moon-stone, got %v", conf.inventory.Items)
+	}
+
+	// A save that can't be read leaves the current profile alone
+	err = os.WriteFile(profilePath("misty"), []byte("{not json"), 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pokedex := conf.pokedex
+	err = switchProfile(conf, "misty", false)
+	if err == nil {
+		t.Fatalf("expected an error switching to a corrupt save")
+	}
+	if conf.profile != defaultProfile || conf.pokedex != pokedex || conf.mapLimit != locationPageSize {
+		t.Errorf("expected a failed switch to keep the default profile, got %s", conf.profile)
+	}
+}
+
+func TestLockProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	err := lockProfile("brock")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := lockProfile("brock"); err != nil {
+		t.Errorf("expected locking a profile twice from one process to work, got %v", err)
+	}
+	unlockProfile("brock")
+	if _, err := os.Stat(lockPath("brock")); err == nil {
+		t.Errorf("expected the lock to be removed")
+	}
+
+	// The process running the tests stands in for another pokedex
+	err = os.WriteFile(lockPath("brock"), []byte(strconv.Itoa(os.Getppid())), 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := lockProfile("brock"); err == nil {
+		t.Errorf("expected a profile in use to be refused")
+	}
+	unlockProfile("brock")
+	if _, err := os.Stat(lockPath("brock")); err != nil {
+		t.Errorf("expected another process's lock to be left alone")
+	}
+
+	err = os.WriteFile(lockPath("brock"), []byte("not a pid"), 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := lockProfile("brock"); err != nil {
+		t.Errorf("expected a stale lock to be taken over, got %v", err)
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queryEntry is everything a pokedex query can look at for one species.
+type queryEntry struct {
+	number    int
+	name      string
+	types     []string
+	abilities []string
+	moves     []string
+	stats     map[string]int
+	height    int
+	weight    int
+	caughtAt  time.Time
+}
+
+// queryTerm is one condition such as "type:water", "bst>500" or the negated
+// "-ability:levitate".
+type queryTerm struct {
+	field  string
+	op     string
+	value  string
+	negate bool
+}
+
+type dexQuery []queryTerm
+
+// queryOperators are checked in order so that ">=" is found before ">".
+var queryOperators = []string{">=", "<=", "!=", ">", "<", "=", ":"}
+
+// statAliases lets queries use the short stat names players are used to.
+var statAliases = map[string]string{
+	"atk":   "attack",
+	"def":   "defense",
+	"spatk": "special-attack",
+	"spa":   "special-attack",
+	"spdef": "special-defense",
+	"spd":   "special-defense",
+	"spe":   "speed",
+}
+
+func isQueryTerm(arg string) bool {
+	for _, op := range queryOperators {
+		if strings.Contains(arg, op) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseQuery(terms []string) (dexQuery, error) {
+	query := dexQuery{}
+	for _, text := range terms {
+		term := queryTerm{}
+		if rest, ok := strings.CutPrefix(text, "-"); ok {
+			term.negate = true
+			text = rest
+		}
+
+		for _, op := range queryOperators {
+			if field, value, ok := strings.Cut(text, op); ok {
+				term.field, term.op, term.value = field, op, value
+				break
+			}
+		}
+		if len(term.op) == 0 || len(term.field) == 0 || len(term.value) == 0 {
+			return nil, fmt.Errorf("Can't understand query term %s", text)
+		}
+		if alias, ok := statAliases[term.field]; ok {
+			term.field = alias
+		}
+
+		switch term.field {
+		case "type", "ability", "move", "name":
+			if term.op != ":" && term.op != "=" && term.op != "!=" {
+				return nil, fmt.Errorf("%s can only be matched with : or !=", term.field)
+			}
+		default:
+			if !isNumericField(term.field) {
+				return nil, fmt.Errorf("Unknown query field %s", term.field)
+			}
+			if _, err := strconv.Atoi(term.value); err != nil {
+				return nil, fmt.Errorf("%s needs a number, got %s", term.field, term.value)
+			}
+		}
+
+		query = append(query, term)
+	}
+	return query, nil
+}
+
+func isNumericField(field string) bool {
+	return field == "bst" || field == "id" || field == "height" || field == "weight" || isStatName(field)
+}
+
+func (q dexQuery) matches(entry queryEntry) bool {
+	for _, term := range q {
+		if term.matches(entry) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (t queryTerm) matches(entry queryEntry) bool {
+	switch t.field {
+	case "type":
+		return t.compareText(containsString(entry.types, t.value))
+	case "ability":
+		return t.compareText(containsString(entry.abilities, t.value))
+	case "move":
+		return t.compareText(containsString(entry.moves, t.value))
+	case "name":
+		return t.compareText(strings.Contains(entry.name, t.value))
+	}
+
+	value, _ := strconv.Atoi(t.value)
+	actual := entry.numericField(t.field)
+	switch t.op {
+	case ">":
+		return actual > value
+	case "<":
+		return actual < value
+	case ">=":
+		return actual >= value
+	case "<=":
+		return actual <= value
+	case "!=":
+		return actual != value
+	default:
+		return actual == value
+	}
+}
+
+func (t queryTerm) compareText(found bool) bool {
+	if t.op == "!=" {
+		return !found
+	}
+	return found
+}
+
+func (e queryEntry) numericField(field string) int {
+	switch field {
+	case "bst":
+		return e.bst()
+	case "id":
+		return e.number
+	case "height":
+		return e.height
+	case "weight":
+		return e.weight
+	}
+	return e.stats[field]
+}
+
+// bst is the base stat total.
+func (e queryEntry) bst() int {
+	total := 0
+	for _, value := range e.stats {
+		total += value
+	}
+	return total
+}
+
+// sortEntries orders entries by id, name, caught (first caught date) or bst.
+// Base stat totals sort highest first, everything else lowest first.
+func sortEntries(entries []queryEntry, by string, reverse bool) error {
+	var less func(a, b queryEntry) bool
+	switch by {
+	case "", "id":
+		less = func(a, b queryEntry) bool { return a.number < b.number }
+	case "name":
+		less = func(a, b queryEntry) bool { return a.name < b.name }
+	case "caught":
+		less = func(a, b queryEntry) bool { return a.caughtAt.Before(b.caughtAt) }
+	case "bst":
+		less = func(a, b queryEntry) bool { return a.bst() > b.bst() }
+	default:
+		return fmt.Errorf("Can't sort by %s, use id, name, caught or bst", by)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if reverse {
+			return less(entries[j], entries[i])
+		}
+		return less(entries[i], entries[j])
+	})
+	return nil
+}
+
+// flagQueryTerms turns the --type and --min-stat flags into query terms, so
+// "--min-stat speed=100,attack=80" becomes "speed>=100 attack>=80".
+func flagQueryTerms(flags map[string]string) []string {
+	terms := []string{}
+	if poketype, ok := flags["type"]; ok {
+		terms = append(terms, "type:"+poketype)
+	}
+	if minStats, ok := flags["min-stat"]; ok {
+		for _, pair := range strings.Split(minStats, ",") {
+			stat, value, _ := strings.Cut(pair, "=")
+			terms = append(terms, stat+">="+value)
+		}
+	}
+	return terms
+}
+
+func (c *apiClient) getPokemonForSpecies(name string) (Pokemon, error) {
+	pokemon, err := c.getPokemon(name)
+	if err == nil {
+		return pokemon, nil
+	}
+
+	species, speciesErr := c.getSpeciesByName(name)
+	if speciesErr != nil {
+		return pokemon, err
+	}
+	return c.getPokemon(species.defaultVariety())
+}
+
+func newQueryEntry(conf *config, entry dexEntry) (queryEntry, error) {
+	pokemon, err := conf.api.getPokemonForSpecies(entry.species)
+	if err != nil {
+		return queryEntry{}, err
+	}
+
+	result := queryEntry{
+		number:   entry.number,
+		name:     entry.species,
+		stats:    map[string]int{},
+		height:   pokemon.Height,
+		weight:   pokemon.Weight,
+		caughtAt: conf.pokedex.SpeciesCaught[entry.species],
+	}
+	for _, poketype := range pokemon.Types {
+		result.types = append(result.types, poketype.Type.Name)
+	}
+	for _, ability := range pokemon.Abilities {
+		result.abilities = append(result.abilities, ability.Ability.Name)
+	}
+	for _, move := range pokemon.Moves {
+		result.moves = append(result.moves, move.Move.Name)
+	}
+	for _, stat := range pokemon.Stats {
+		result.stats[stat.Stat.Name] = stat.BaseStat
+	}
+
+	return result, nil
+}
+
+// showQuery lists the caught species in scope that match the query. Every
+// term has to be a query, so a stray species name isn't silently ignored.
+func showQuery(conf *config, scope []dexEntry, terms []string, flags map[string]string) error {
+	for _, term := range terms {
+		if !isQueryTerm(term) {
+			return fmt.Errorf("%s isn't a query term, use terms like type:water or bst>500", term)
+		}
+	}
+
+	query, err := parseQuery(append(flagQueryTerms(flags), terms...))
+	if err != nil {
+		return err
+	}
+
+	matches := []queryEntry{}
+	for _, entry := range scope {
+		if conf.pokedex.Status(entry.species) != "caught" {
+			continue
+		}
+		result, err := newQueryEntry(conf, entry)
+		if err != nil {
+			return err
+		}
+		if query.matches(result) {
+			matches = append(matches, result)
+		}
+	}
+
+	err = sortEntries(matches, flags["sort"], flags["reverse"] == "true")
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		fmt.Fprintln(conf.out, "No caught pokemon match")
+		return nil
+	}
+
+	for _, match := range matches {
+		fmt.Fprintf(conf.out, "#%04d %-14s %-18s BST %3d  caught %s\n",
+			match.number, match.name, strings.Join(match.types, "/"), match.bst(), match.caughtAt.Format("2006-01-02"))
+	}
+	fmt.Fprintf(conf.out, "%d caught pokemon match\n", len(matches))
+
+	return nil
+}
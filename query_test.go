@@ -0,0 +1,134 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var testEntries = []queryEntry{
+	{
+		number:    130,
+		name:      "gyarados",
+		types:     []string{"water", "flying"},
+		abilities: []string{"intimidate", "moxie"},
+		moves:     []string{"surf", "bite"},
+		stats:     map[string]int{"hp": 95, "attack": 125, "defense": 79, "special-attack": 60, "special-defense": 100, "speed": 81},
+		caughtAt:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		number:    25,
+		name:      "pikachu",
+		types:     []string{"electric"},
+		abilities: []string{"static", "lightning-rod"},
+		moves:     []string{"thunderbolt"},
+		stats:     map[string]int{"hp": 35, "attack": 55, "defense": 40, "special-attack": 50, "special-defense": 50, "speed": 90},
+		caughtAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		number:    7,
+		name:      "squirtle",
+		types:     []string{"water"},
+		abilities: []string{"torrent", "rain-dish"},
+		moves:     []string{"surf", "bubble"},
+		stats:     map[string]int{"hp": 44, "attack": 48, "defense": 65, "special-attack": 50, "special-defense": 64, "speed": 43},
+		caughtAt:  time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+func TestQuery(t *testing.T) {
+	cases := []struct {
+		terms    []string
+		expected []string
+	}{
+		{terms: []string{"type:water"}, expected: []string{"gyarados", "squirtle"}},
+		{terms: []string{"type:water", "bst>500"}, expected: []string{"gyarados"}},
+		{terms: []string{"-type:water"}, expected: []string{"pikachu"}},
+		{terms: []string{"spe>=90"}, expected: []string{"pikachu"}},
+		{terms: []string{"ability:torrent"}, expected: []string{"squirtle"}},
+		{terms: []string{"move:surf", "id<100"}, expected: []string{"squirtle"}},
+		{terms: []string{"name:chu"}, expected: []string{"pikachu"}},
+		{terms: []string{"type!=water"}, expected: []string{"pikachu"}},
+	}
+
+	for _, c := range cases {
+		query, err := parseQuery(c.terms)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.terms, err)
+			continue
+		}
+
+		actual := []string{}
+		for _, entry := range testEntries {
+			if query.matches(entry) {
+				actual = append(actual, entry.name)
+			}
+		}
+		if len(actual) != len(c.expected) {
+			t.Errorf("%v: actual %v != expected %v", c.terms, actual, c.expected)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != c.expected[i] {
+				t.Errorf("%v: actual %v != expected %v", c.terms, actual, c.expected)
+			}
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	bad := []string{"luck>3", "bst>lots", "type>water", "type:", ":water"}
+	for _, term := range bad {
+		if _, err := parseQuery([]string{term}); err == nil {
+			t.Errorf("%v: expected an error", term)
+		}
+	}
+}
+
+func TestSortEntries(t *testing.T) {
+	cases := []struct {
+		by       string
+		reverse  bool
+		expected []string
+	}{
+		{by: "id", expected: []string{"squirtle", "pikachu", "gyarados"}},
+		{by: "name", expected: []string{"gyarados", "pikachu", "squirtle"}},
+		{by: "caught", expected: []string{"pikachu", "squirtle", "gyarados"}},
+		{by: "bst", expected: []string{"gyarados", "pikachu", "squirtle"}},
+		{by: "bst", reverse: true, expected: []string{"squirtle", "pikachu", "gyarados"}},
+	}
+
+	for _, c := range cases {
+		entries := append([]queryEntry{}, testEntries...)
+		err := sortEntries(entries, c.by, c.reverse)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, entry := range entries {
+			if entry.name != c.expected[i] {
+				t.Errorf("sort by %s: position %d is %s, expected %s", c.by, i, entry.name, c.expected[i])
+			}
+		}
+	}
+
+	if err := sortEntries(testEntries, "weight", false); err == nil {
+		t.Errorf("expected an error for an unknown sort")
+	}
+}
+
+func TestShowQueryTerms(t *testing.T) {
+	conf, out := newTestConfig(t, nil)
+
+	err := showQuery(conf, []dexEntry{}, []string{"pikachu", "type:electric"}, map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), "pikachu") {
+		t.Errorf("actual %v != expected an error for pikachu", err)
+	}
+
+	err = showQuery(conf, []dexEntry{}, []string{"type:electric", "-bst>500"}, map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "No caught pokemon match\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
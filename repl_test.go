@@ -1,40 +1,103 @@
 package main
 
 import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "strings"
     "testing"
+    "time"
 )
 
-func TestCleanInput(t *testing.T) {
-    cases := []struct {
-        input           string
-        expected        []string
-    }{
-        {
-            input:      " hello world ",
-            expected:   []string{"hello", "world"},
-        },
-        {
-            input:      " cool test    for cool people  ",
-            expected:   []string{"cool", "test", "for", "cool", "people"},
-        },
-        {
-            input:      "single",
-            expected:   []string{"single"},
-        },
-    }
-
-    for _, c := range cases {
-        actual := cleanInput(c.input)
-        if len(actual) != len(c.expected) {
-            t.Errorf("actual length: %v != expected length: %v", len(actual), len(c.expected))
-        }
+const viridianForestJson = `{
+    "name": "viridian-forest-area",
+    "location": {"name": "viridian-forest"},
+    "pokemon_encounters": [{"pokemon": {"name": "caterpie"}}, {"pokemon": {"name": "pikachu"}}]
+}`
+
+// The pokemon found in viridian forest, which exploring looks up the species of
+const caterpieJson = `{"name": "caterpie", "species": {"name": "caterpie"}}`
+const pikachuJson = `{"name": "pikachu", "species": {"name": "pikachu"}}`
+
+// newTestConfig starts a new trainer whose API responses are already cached,
+// so no requests reach the real API. Command output goes to the buffer.
+func newTestConfig(t *testing.T, responses map[string]string) (*config, *bytes.Buffer) {
+    out := &bytes.Buffer{}
+    conf := &config{
+        out:        out,
+        pokedex:    NewPokedex(),
+        storage:    NewStorage(),
+        inventory:  NewInventory(),
+        api:        newApiClient(time.Minute),
+        mapLimit:   locationPageSize,
+    }
+    for url, data := range responses {
+        conf.api.cache.Add(url, []byte(data))
+    }
+    conf.profile = defaultProfile
+    conf.saveFile = filepath.Join(t.TempDir(), "save.json")
+    return conf, out
+}
 
-        for i := range actual {
-            word := actual[i]
-            expectedWord := c.expected[i]
-            if word != expectedWord {
-                t.Errorf("word: %v != expectedWord: %v", word, expectedWord)
-            }
+func TestParseFlags(t *testing.T) {
+    flags, positional, err := parseFlags([]string{"--missing", "--region", "kanto", "pikachu", "--sort=name"}, "region")
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    expected := map[string]string{
+        "missing":  "true",
+        "region":   "kanto",
+        "sort":     "name",
+    }
+    for key, value := range expected {
+        if flags[key] != value {
+            t.Errorf("flag %v: %v != expected %v", key, flags[key], value)
         }
     }
+    if len(positional) != 1 || positional[0] != "pikachu" {
+        t.Errorf("positional: %v != expected [pikachu]", positional)
+    }
+
+    if _, _, err := parseFlags([]string{"--region"}, "region"); err == nil {
+        t.Errorf("expected an error for a flag missing its value")
+    }
+}
+
+func TestSessionCommands(t *testing.T) {
+    initCommands()
+    conf, out := newTestConfig(t, map[string]string{
+        "https://pokeapi.co/api/v2/location-area/viridian-forest-area/": viridianForestJson,
+        "https://pokeapi.co/api/v2/pokemon/caterpie/": caterpieJson,
+        "https://pokeapi.co/api/v2/pokemon/pikachu/": pikachuJson,
+    })
+
+    session := newSession(conf, strings.NewReader("explore viridian-forest-area\nnonsense\nexit\nhelp\n"), out)
+    err := session.Run()
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    expected := "Pokedex > Found Pokemon:\n- caterpie\n- pikachu\nPokedex > Unknown command\nPokedex > Closing the Pokedex... Goodbye!\n"
+    if out.String() != expected {
+        t.Errorf("actual %q != expected %q", out.String(), expected)
+    }
+    if conf.currentArea != "viridian-forest-area" || !conf.pokedex.Seen["pikachu"] {
+        t.Errorf("explore didn't update the game")
+    }
+    if _, err := os.Stat(conf.saveFile); err != nil {
+        t.Errorf("expected the game to be saved: %v", err)
+    }
+
+    out.Reset()
+    conf.battle = &activeBattle{}
+    err = session.Execute("explore viridian-forest-area")
+    if err == nil || out.Len() > 0 {
+        t.Errorf("expected explore to be refused during a battle, got %v and %q", err, out.String())
+    }
+
+    err = session.Execute("help")
+    if err != nil || !strings.Contains(out.String(), "explore: ") {
+        t.Errorf("help output %q is missing explore", out.String())
+    }
 }
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// saveVersion is bumped whenever the layout of trainerSave changes.
+// Version 2 added the location and settings, and version 3 the inventory.
+const saveVersion = 3
+
+type trainerSave struct {
+	Version   int           `json:"version"`
+	Pokedex   *Pokedex      `json:"pokedex"`
+	Storage   *Storage      `json:"storage"`
+	Inventory *Inventory    `json:"inventory"`
+	Location  savedLocation `json:"location"`
+	Settings  settings      `json:"settings"`
+}
+
+// savedLocation is where the trainer was, so a profile picks up where it
+// left off.
+type savedLocation struct {
+	Area   string `json:"area,omitempty"`
+	Region string `json:"region,omitempty"`
+	Page   int    `json:"page,omitempty"`
+}
+
+func defaultSavePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "pokedex_save.json"
+	}
+	return filepath.Join(home, ".pokedexcli", "save.json")
+}
+
+// loadSave restores the pokedex, storage, location and settings from path.
+// A missing file just means a new trainer.
+func loadSave(path string, conf *config) error {
+	save, err := readSave(path)
+	if errors.Is(err, os.ErrNotExist) {
+		save = trainerSave{Pokedex: NewPokedex(), Storage: NewStorage(), Inventory: NewInventory()}
+	} else if err != nil {
+		return err
+	}
+
+	conf.pokedex = save.Pokedex
+	conf.storage = save.Storage
+	conf.inventory = save.Inventory
+	conf.currentArea = save.Location.Area
+	conf.mapRegion = save.Location.Region
+	conf.mapPage = save.Location.Page
+	conf.settings = save.Settings
+	conf.mapLimit = save.Settings.pageSize()
+	return nil
+}
+
+// readSave decodes and checks a save file, filling in anything older
+// versions didn't record.
+func readSave(path string) (trainerSave, error) {
+	var save trainerSave
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return save, err
+	}
+
+	err = json.Unmarshal(data, &save)
+	if err != nil {
+		return save, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+	if save.Version < 1 {
+		return save, fmt.Errorf("%s is not a pokedex save file", path)
+	}
+	if save.Version > saveVersion {
+		return save, fmt.Errorf("Save file version %d is newer than this pokedex supports (%d)", save.Version, saveVersion)
+	}
+
+	if save.Pokedex == nil {
+		save.Pokedex = NewPokedex()
+	}
+	if save.Pokedex.Caught == nil {
+		save.Pokedex.Caught = map[int]*CaughtPokemon{}
+	}
+	if save.Pokedex.Seen == nil {
+		save.Pokedex.Seen = map[string]bool{}
+	}
+	if save.Pokedex.SpeciesCaught == nil {
+		save.Pokedex.SpeciesCaught = map[string]time.Time{}
+	}
+	for _, caught := range save.Pokedex.Caught {
+		save.Pokedex.MarkCaught(caught.dexSpecies(), caught.CaughtAt)
+	}
+	if save.Storage == nil {
+		save.Storage = NewStorage()
+	}
+	save.Storage.Reconcile(save.Pokedex)
+	if save.Inventory == nil {
+		save.Inventory = NewInventory()
+	}
+	if save.Inventory.Items == nil {
+		save.Inventory.Items = map[string]int{}
+	}
+
+	return save, nil
+}
+
+func writeSave(path string, conf *config) error {
+	return writeSaves(map[string]trainerSave{path: currentSave(conf)})
+}
+
+// currentSave gathers the state of the trainer that's playing.
+func currentSave(conf *config) trainerSave {
+	return trainerSave{
+		Version:   saveVersion,
+		Pokedex:   conf.pokedex,
+		Storage:   conf.storage,
+		Inventory: conf.inventory,
+		Location: savedLocation{
+			Area:   conf.currentArea,
+			Region: conf.mapRegion,
+			Page:   conf.mapPage,
+		},
+		Settings: conf.settings,
+	}
+}
+
+// copySave makes a deep copy of a save, so changes to the copy can be thrown
+// away.
+func copySave(save trainerSave) (trainerSave, error) {
+	var copied trainerSave
+	data, err := json.Marshal(save)
+	if err != nil {
+		return copied, err
+	}
+	err = json.Unmarshal(data, &copied)
+	return copied, err
+}
+
+// writeSaves writes several saves together. Every file is written to a
+// temporary file first, then they're moved into place in path order with the
+// old saves kept aside as backups. A failure part way through puts the saves
+// already replaced back, so either all of them change or none do.
+func writeSaves(saves map[string]trainerSave) error {
+	paths := []string{}
+	for path := range saves {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	defer func() {
+		for _, path := range paths {
+			os.Remove(path + ".tmp")
+		}
+	}()
+
+	for _, path := range paths {
+		data, err := json.MarshalIndent(saves[path], "", "  ")
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+
+		// Write to a temporary file first so a crash never leaves a half written save
+		err = os.WriteFile(path+".tmp", data, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	replaced := []string{}
+	hadSave := map[string]bool{}
+	rollback := func() {
+		for _, path := range replaced {
+			if hadSave[path] {
+				os.Rename(path+".bak", path)
+			} else {
+				os.Remove(path)
+			}
+		}
+	}
+
+	for _, path := range paths {
+		err := os.Rename(path, path+".bak")
+		if err == nil {
+			hadSave[path] = true
+		} else if !errors.Is(err, os.ErrNotExist) {
+			rollback()
+			return err
+		}
+
+		err = os.Rename(path+".tmp", path)
+		if err != nil {
+			if hadSave[path] {
+				os.Rename(path+".bak", path)
+			}
+			rollback()
+			return err
+		}
+		replaced = append(replaced, path)
+	}
+
+	for _, path := range replaced {
+		os.Remove(path + ".bak")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteSavesRollsBack(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.json")
+	second := filepath.Join(dir, "b.json")
+	for _, path := range []string{first, second} {
+		err := os.WriteFile(path, []byte("old"), 0644)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// A directory in the way of the second backup makes it fail after the
+	// first save was replaced
+	err := os.MkdirAll(filepath.Join(second+".bak", "blocked"), 0755)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	save := trainerSave{Version: saveVersion, Pokedex: NewPokedex(), Storage: NewStorage(), Inventory: NewInventory()}
+	save.Inventory.Add("fire-stone", 1)
+	err = writeSaves(map[string]trainerSave{first: save, second: save})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, path := range []string{first, second} {
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != "old" {
+			t.Errorf("%s: actual %q, %v != expected the old save", filepath.Base(path), data, err)
+		}
+	}
+	if _, err := os.Stat(first + ".bak"); err == nil {
+		t.Errorf("expected the backup to be moved back")
+	}
+
+	os.RemoveAll(second + ".bak")
+	err = writeSaves(map[string]trainerSave{first: save, second: save})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, path := range []string{first, second} {
+		loaded, err := readSave(path)
+		if err != nil || loaded.Inventory.Count("fire-stone") != 1 {
+			t.Errorf("%s: expected the new save, got %v", filepath.Base(path), err)
+		}
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "*.*.*"))
+	if len(leftovers) > 0 {
+		t.Errorf("unexpected files left behind %v", leftovers)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mikeheiberger/pokedexcli/internal/pokedexapi"
+)
+
+// server exposes the pokedex over HTTP. Every request shares the one
+// trainer's state, so requests are handled one at a time.
+type server struct {
+	mutex sync.Mutex
+	conf  *config
+}
+
+// httpError carries the status code a handler wants to reply with.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e httpError) Error() string {
+	return e.err.Error()
+}
+
+func notFound(err error) error {
+	return httpError{http.StatusNotFound, err}
+}
+
+func badRequest(err error) error {
+	return httpError{http.StatusBadRequest, err}
+}
+
+type locationsResponse struct {
+	Page    int      `json:"page"`
+	Pages   int      `json:"pages"`
+	Count   int      `json:"count"`
+	Results []string `json:"results"`
+}
+
+type exploreResponse struct {
+	Name     string   `json:"name"`
+	Location string   `json:"location"`
+	Pokemon  []string `json:"pokemon"`
+}
+
+type catchResponse struct {
+	Species string         `json:"species"`
+	Caught  bool           `json:"caught"`
+	InParty bool           `json:"in_party,omitempty"`
+	Pokemon *CaughtPokemon `json:"pokemon,omitempty"`
+	// Warning and SaveError say what went wrong after the catch, which
+	// still counts
+	Warning   string `json:"warning,omitempty"`
+	SaveError string `json:"save_error,omitempty"`
+}
+
+type inspectResponse struct {
+	Pokemon *CaughtPokemon `json:"pokemon"`
+	Types   []string       `json:"types"`
+	Stats   map[string]int `json:"stats"`
+	Height  int            `json:"height"`
+	Weight  int            `json:"weight"`
+}
+
+type pokedexResponse struct {
+	Seen    int              `json:"seen"`
+	Caught  int              `json:"caught"`
+	Pokemon []*CaughtPokemon `json:"pokemon"`
+}
+
+func newServer(conf *config) http.Handler {
+	s := &server{conf: conf}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /locations", s.handle(s.listLocations, false))
+	mux.HandleFunc("GET /locations/{area}", s.handle(s.exploreArea, true))
+	mux.HandleFunc("POST /catch/{pokemon}", s.handle(s.catchPokemon, false))
+	mux.HandleFunc("GET /pokemon/{id}", s.handle(s.inspectPokemon, false))
+	mux.HandleFunc("GET /pokedex", s.handle(s.listPokedex, false))
+	return mux
+}
+
+// handle wraps an endpoint, writing its result as JSON and saving afterwards
+// when the endpoint changes the game.
+func (s *server) handle(endpoint func(*http.Request) (any, error), saves bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		result, err := endpoint(r)
+		if err == nil && saves {
+			err = writeSave(s.conf.saveFile, s.conf)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			status := http.StatusInternalServerError
+			if httpErr, ok := err.(httpError); ok {
+				status = httpErr.status
+			}
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	text := r.URL.Query().Get(name)
+	if len(text) == 0 {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < 1 {
+		return 0, badRequest(fmt.Errorf("%s needs a positive number, got %s", name, text))
+	}
+	return value, nil
+}
+
+func (s *server) listLocations(r *http.Request) (any, error) {
+	page, err := queryInt(r, "page", 1)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := queryInt(r, "limit", s.conf.settings.pageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	var locations LocationsResponse
+	err = s.conf.api.getJsonResource(locationAreaPageUrl(page, limit), &locations)
+	if err != nil {
+		return nil, err
+	}
+	pages := pageCount(locations.Count, limit)
+	if page > pages {
+		return nil, notFound(fmt.Errorf("There are only %d pages", pages))
+	}
+
+	response := locationsResponse{Page: page, Pages: pages, Count: locations.Count, Results: []string{}}
+	for _, loc := range locations.Results {
+		response.Results = append(response.Results, loc.Name)
+	}
+	return response, nil
+}
+
+func (s *server) exploreArea(r *http.Request) (any, error) {
+	area := strings.ToLower(r.PathValue("area"))
+	if !validResourceName(area) {
+		return nil, badRequest(fmt.Errorf("%s isn't an area name", area))
+	}
+
+	explore, err := s.conf.api.getLocationArea(area)
+	if errors.Is(err, pokedexapi.ErrNotFound) {
+		return nil, notFound(fmt.Errorf("There's no area named %s", area))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.conf.currentArea = explore.Name
+	response := exploreResponse{Name: explore.Name, Location: explore.Location.Name, Pokemon: []string{}}
+	for _, encounter := range explore.PokemonEncounters {
+		response.Pokemon = append(response.Pokemon, encounter.Pokemon.Name)
+		s.conf.pokedex.MarkSeen(s.conf.api.speciesOf(encounter.Pokemon.Name))
+	}
+	return response, nil
+}
+
+// validResourceName checks a name from a URL before it's used to build an
+// API URL of its own.
+func validResourceName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// catchPokemon saves the game itself, since by the time the save is written
+// the catch has happened and a failed save shouldn't hide it.
+func (s *server) catchPokemon(r *http.Request) (any, error) {
+	name := strings.ToLower(r.PathValue("pokemon"))
+	if !validResourceName(name) {
+		return nil, badRequest(fmt.Errorf("%s isn't a pokemon name", name))
+	}
+
+	pokemon, caught, err := throwPokeball(s.conf, name)
+	if errors.Is(err, pokedexapi.ErrNotFound) {
+		return nil, notFound(fmt.Errorf("There's no pokemon named %s", name))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := catchResponse{Species: pokemon.Species.Name, Caught: caught != nil}
+	if caught != nil {
+		response.InParty, err = storeCaught(s.conf, pokemon, caught)
+		if err != nil {
+			response.Warning = err.Error()
+		}
+		response.Pokemon = caught
+	}
+
+	err = writeSave(s.conf.saveFile, s.conf)
+	if err != nil {
+		response.SaveError = err.Error()
+	}
+	return response, nil
+}
+
+func (s *server) inspectPokemon(r *http.Request) (any, error) {
+	caught, err := s.conf.pokedex.Find(r.PathValue("id"))
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	details, err := s.conf.api.newPokemonDetails(caught)
+	if err != nil {
+		return nil, err
+	}
+	return inspectResponse{
+		Pokemon: caught,
+		Types:   details.types,
+		Stats:   details.stats,
+		Height:  details.pokemon.Height,
+		Weight:  details.pokemon.Weight,
+	}, nil
+}
+
+func (s *server) listPokedex(r *http.Request) (any, error) {
+	species := r.URL.Query().Get("species")
+	response := pokedexResponse{Seen: len(s.conf.pokedex.Seen), Caught: len(s.conf.pokedex.SpeciesCaught), Pokemon: []*CaughtPokemon{}}
+	for _, caught := range s.conf.pokedex.Sorted() {
+		if len(species) == 0 || caught.isSpecies(species) {
+			response.Pokemon = append(response.Pokemon, caught)
+		}
+	}
+	return response, nil
+}
+
+func serve(conf *config, addr string) error {
+	fmt.Fprintf(conf.out, "Serving the pokedex on %s\n", addr)
+	return http.ListenAndServe(addr, newServer(conf))
+}
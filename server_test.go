@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestServer serves a fresh pokedex with API responses already cached,
+// so no requests reach the real API.
+func newTestServer(t *testing.T) (*httptest.Server, *config) {
+	conf, _ := newTestConfig(t, map[string]string{
+		locationAreaPageUrl(1, 2): `{"count": 3, "results": [{"name": "canalave-city-area"}, {"name": "eterna-city-area"}]}`,
+		"https://pokeapi.co/api/v2/location-area/viridian-forest-area/": viridianForestJson,
+		"https://pokeapi.co/api/v2/pokemon/caterpie/":                   caterpieJson,
+		"https://pokeapi.co/api/v2/pokemon/pikachu/": `{
+			"name": "pikachu",
+			"height": 4,
+			"weight": 60,
+			"stats": [{"base_stat": 35, "stat": {"name": "hp"}}, {"base_stat": 90, "stat": {"name": "speed"}}],
+			"types": [{"slot": 1, "type": {"name": "electric"}}]
+		}`,
+	})
+	conf.pokedex.Add(&CaughtPokemon{Species: "pikachu", Level: 50, Nature: "hardy", IVs: map[string]int{}, EVs: map[string]int{}})
+
+	server := httptest.NewServer(newServer(conf))
+	t.Cleanup(server.Close)
+	return server, conf
+}
+
+func getJson(t *testing.T, url string, status int, target any) {
+	res, err := http.Get(url)
+	checkJson(t, url, res, err, status, target)
+}
+
+func postJson(t *testing.T, url string, status int, target any) {
+	res, err := http.Post(url, "application/json", nil)
+	checkJson(t, url, res, err, status, target)
+}
+
+func checkJson(t *testing.T, url string, res *http.Response, err error, status int, target any) {
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != status {
+		t.Fatalf("%s: status %d, expected %d", url, res.StatusCode, status)
+	}
+	err = json.NewDecoder(res.Body).Decode(target)
+	if err != nil {
+		t.Fatalf("%s: %v", url, err)
+	}
+}
+
+func TestServerLocations(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var locations locationsResponse
+	getJson(t, server.URL+"/locations?limit=2", http.StatusOK, &locations)
+	if locations.Pages != 2 || len(locations.Results) != 2 || locations.Results[1] != "eterna-city-area" {
+		t.Errorf("unexpected locations %+v", locations)
+	}
+
+	var failure map[string]string
+	getJson(t, server.URL+"/locations?page=zero", http.StatusBadRequest, &failure)
+	if len(failure["error"]) == 0 {
+		t.Errorf("expected an error message")
+	}
+}
+
+func TestServerExplore(t *testing.T) {
+	server, conf := newTestServer(t)
+
+	var explore exploreResponse
+	getJson(t, server.URL+"/locations/viridian-forest-area", http.StatusOK, &explore)
+	if explore.Location != "viridian-forest" || len(explore.Pokemon) != 2 {
+		t.Errorf("unexpected area %+v", explore)
+	}
+	if conf.currentArea != "viridian-forest-area" || conf.pokedex.Status("caterpie") != "seen" {
+		t.Errorf("exploring should move the trainer and mark pokemon as seen")
+	}
+	if _, err := os.Stat(conf.saveFile); err != nil {
+		t.Errorf("expected the game to be saved: %v", err)
+	}
+
+	var failure map[string]string
+	getJson(t, server.URL+"/locations/viridian%24forest", http.StatusBadRequest, &failure)
+	if len(failure["error"]) == 0 {
+		t.Errorf("expected an error message")
+	}
+
+	// An area that can't be decoded is the server's fault, not a missing area
+	conf.api.cache.Add("https://pokeapi.co/api/v2/location-area/broken-area/", []byte("not json"))
+	getJson(t, server.URL+"/locations/broken-area", http.StatusInternalServerError, &failure)
+}
+
+func TestServerInspect(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var inspect inspectResponse
+	getJson(t, server.URL+"/pokemon/1", http.StatusOK, &inspect)
+	if inspect.Pokemon.Species != "pikachu" || inspect.Stats["hp"] != 95 || inspect.Stats["speed"] != 95 || inspect.Types[0] != "electric" {
+		t.Errorf("unexpected pokemon %+v", inspect)
+	}
+
+	var failure map[string]string
+	getJson(t, server.URL+"/pokemon/7", http.StatusNotFound, &failure)
+}
+
+func TestServerPokedex(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var dex pokedexResponse
+	getJson(t, server.URL+"/pokedex?species=pikachu", http.StatusOK, &dex)
+	if len(dex.Pokemon) != 1 || dex.Pokemon[0].ID != 1 {
+		t.Errorf("unexpected pokedex %+v", dex)
+	}
+
+	getJson(t, server.URL+"/pokedex?species=eevee", http.StatusOK, &dex)
+	if len(dex.Pokemon) != 0 {
+		t.Errorf("expected no eevee, got %+v", dex.Pokemon)
+	}
+}
+
+func TestServerCatch(t *testing.T) {
+	server, conf := newTestServer(t)
+	conf.api.cache.Add("https://pokeapi.co/api/v2/pokemon/pikachu/", []byte(`{
+		"name": "pikachu",
+		"base_experience": 112,
+		"species": {"name": "pikachu", "url": "https://pokeapi.co/api/v2/pokemon-species/25/"}
+	}`))
+	conf.api.cache.Add("https://pokeapi.co/api/v2/pokemon-species/25/", []byte(`{
+		"name": "pikachu",
+		"growth_rate": {"name": "medium", "url": "https://pokeapi.co/api/v2/growth-rate/2/"}
+	}`))
+	conf.api.cache.Add("https://pokeapi.co/api/v2/growth-rate/2/", []byte(`{"name": "medium", "levels": []}`))
+
+	var failure map[string]string
+	postJson(t, server.URL+"/catch/pika%24chu", http.StatusBadRequest, &failure)
+
+	// The save file can't be written inside a file, but the catch still counts
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	err := os.WriteFile(blocker, []byte{}, 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conf.saveFile = filepath.Join(blocker, "save.json")
+
+	var catch catchResponse
+	postJson(t, server.URL+"/catch/PIKACHU", http.StatusOK, &catch)
+	if catch.Species != "pikachu" || len(catch.SaveError) == 0 {
+		t.Errorf("unexpected catch %+v", catch)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mikeheiberger/pokedexcli/internal/sprite"
+)
+
+// settings are the preferences each trainer profile keeps.
+type settings struct {
+	PageSize int               `json:"page_size,omitempty"`
+	Color    string            `json:"color,omitempty"`
+	Aliases  map[string]string `json:"aliases,omitempty"`
+	Macros   map[string]string `json:"macros,omitempty"`
+}
+
+func (s settings) pageSize() int {
+	if s.PageSize > 0 {
+		return s.PageSize
+	}
+	return locationPageSize
+}
+
+func commandSet(conf *config, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintf(conf.out, "page-size: %d\n", conf.settings.pageSize())
+		color := conf.settings.Color
+		if len(color) == 0 {
+			color = "auto"
+		}
+		fmt.Fprintf(conf.out, "color: %s\n", color)
+		return nil
+	}
+	if len(args) != 2 {
+		return errors.New("Usage: set <page-size|color> <value>")
+	}
+
+	switch args[0] {
+	case "page-size":
+		size, err := strconv.Atoi(args[1])
+		if err != nil || size < 1 {
+			return fmt.Errorf("page-size needs a positive number, got %s", args[1])
+		}
+		conf.settings.PageSize = size
+		conf.mapLimit = size
+	case "color":
+		if args[1] == "auto" {
+			conf.settings.Color = ""
+			break
+		}
+		if _, err := sprite.ParseMode(args[1]); err != nil {
+			return err
+		}
+		conf.settings.Color = args[1]
+	default:
+		return fmt.Errorf("Unknown setting %s, use page-size or color", args[0])
+	}
+
+	fmt.Fprintf(conf.out, "%s set to %s\n", args[0], args[1])
+	return nil
+}
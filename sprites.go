@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"os"
+	"sort"
+
+	"github.com/mikeheiberger/pokedexcli/internal/pokedexapi"
+	"github.com/mikeheiberger/pokedexcli/internal/sprite"
+)
+
+type spriteOptions struct {
+	shiny   bool
+	back    bool
+	female  bool
+	version string
+	mode    sprite.Mode
+}
+
+// spriteOptionsFromFlags reads --shiny, --back, --version and --color. The
+// color mode is guessed from the terminal unless --color or the color
+// setting picks one.
+func spriteOptionsFromFlags(conf *config, flags map[string]string) (spriteOptions, error) {
+	opts := spriteOptions{
+		shiny:   flags["shiny"] == "true",
+		back:    flags["back"] == "true",
+		version: flags["version"],
+		mode:    sprite.DetectMode(os.Getenv("COLORTERM"), os.Getenv("TERM")),
+	}
+
+	name, ok := flags["color"]
+	if !ok {
+		name, ok = conf.settings.Color, len(conf.settings.Color) > 0
+	}
+	if ok {
+		mode, err := sprite.ParseMode(name)
+		if err != nil {
+			return opts, err
+		}
+		opts.mode = mode
+	}
+	return opts, nil
+}
+
+// spriteKey is the API field name of the sprite, like "back_shiny_female".
+func (o spriteOptions) spriteKey(female bool) string {
+	key := "front"
+	if o.back {
+		key = "back"
+	}
+	if o.shiny {
+		key += "_shiny"
+	} else if !female {
+		key += "_default"
+	}
+	if female {
+		key += "_female"
+	}
+	return key
+}
+
+// versionSprites lists the sprites of every game version by name, such as
+// "red-blue" or "platinum", regardless of their generation.
+func versionSprites(pokemon Pokemon) (map[string]map[string]any, error) {
+	data, err := json.Marshal(pokemon.Sprites.Versions)
+	if err != nil {
+		return nil, err
+	}
+
+	var generations map[string]map[string]map[string]any
+	err = json.Unmarshal(data, &generations)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[string]map[string]any{}
+	for _, generation := range generations {
+		for name, sprites := range generation {
+			versions[name] = sprites
+		}
+	}
+	return versions, nil
+}
+
+func spriteUrl(pokemon Pokemon, opts spriteOptions) (string, error) {
+	sprites := map[string]any{
+		"front_default":      pokemon.Sprites.FrontDefault,
+		"front_shiny":        pokemon.Sprites.FrontShiny,
+		"front_female":       pokemon.Sprites.FrontFemale,
+		"front_shiny_female": pokemon.Sprites.FrontShinyFemale,
+		"back_default":       pokemon.Sprites.BackDefault,
+		"back_shiny":         pokemon.Sprites.BackShiny,
+		"back_female":        pokemon.Sprites.BackFemale,
+		"back_shiny_female":  pokemon.Sprites.BackShinyFemale,
+	}
+
+	if len(opts.version) > 0 {
+		versions, err := versionSprites(pokemon)
+		if err != nil {
+			return "", err
+		}
+		var ok bool
+		sprites, ok = versions[opts.version]
+		if !ok {
+			names := []string{}
+			for name := range versions {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return "", fmt.Errorf("Unknown version %s, try one of: %v", opts.version, names)
+		}
+	}
+
+	// Female sprites only exist for species that look different, otherwise
+	// the regular sprite is used
+	if opts.female {
+		if url, ok := sprites[opts.spriteKey(true)].(string); ok && len(url) > 0 {
+			return url, nil
+		}
+	}
+	if url, ok := sprites[opts.spriteKey(false)].(string); ok && len(url) > 0 {
+		return url, nil
+	}
+	return "", fmt.Errorf("%s has no %s sprite", pokemon.Name, opts.spriteKey(false))
+}
+
+func printSprite(conf *config, pokemon Pokemon, opts spriteOptions) error {
+	url, err := spriteUrl(pokemon, opts)
+	if err != nil {
+		return err
+	}
+
+	asset, err := conf.api.assets.Get(url)
+	if err != nil {
+		return err
+	}
+	if asset.ContentType != "image/png" {
+		return fmt.Errorf("Can't draw a sprite of type %s", asset.ContentType)
+	}
+
+	img, err := png.Decode(bytes.NewReader(asset.Data))
+	if err != nil {
+		return fmt.Errorf("Could not decode sprite: %v", err)
+	}
+
+	for _, line := range sprite.Render(img, opts.mode) {
+		fmt.Fprintln(conf.out, line)
+	}
+	return nil
+}
+
+// spriteDataUri embeds a sprite in a data URI so pages using it need no
+// other files.
+func (c *apiClient) spriteDataUri(pokemon Pokemon, opts spriteOptions) (string, error) {
+	url, err := spriteUrl(pokemon, opts)
+	if err != nil {
+		return "", err
+	}
+
+	asset, err := c.assets.Get(url)
+	if err != nil {
+		return "", err
+	}
+	return assetDataUri(asset), nil
+}
+
+func assetDataUri(asset pokedexapi.Asset) string {
+	return "data:" + asset.ContentType + ";base64," + base64.StdEncoding.EncodeToString(asset.Data)
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestSpriteUrl(t *testing.T) {
+	var pokemon Pokemon
+	pokemon.Name = "pikachu"
+	pokemon.Sprites.FrontDefault = "front.png"
+	pokemon.Sprites.FrontShiny = "front-shiny.png"
+	pokemon.Sprites.FrontFemale = "front-female.png"
+	pokemon.Sprites.BackDefault = "back.png"
+	pokemon.Sprites.Versions.GenerationI.Yellow.FrontDefault = "yellow.png"
+
+	cases := []struct {
+		opts     spriteOptions
+		expected string
+	}{
+		{opts: spriteOptions{}, expected: "front.png"},
+		{opts: spriteOptions{shiny: true}, expected: "front-shiny.png"},
+		{opts: spriteOptions{female: true}, expected: "front-female.png"},
+		{opts: spriteOptions{shiny: true, female: true}, expected: "front-shiny.png"},
+		{opts: spriteOptions{back: true}, expected: "back.png"},
+		{opts: spriteOptions{version: "yellow"}, expected: "yellow.png"},
+	}
+
+	for _, c := range cases {
+		actual, err := spriteUrl(pokemon, c.opts)
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %v", c.opts, err)
+			continue
+		}
+		if actual != c.expected {
+			t.Errorf("%+v: actual %s != expected %s", c.opts, actual, c.expected)
+		}
+	}
+
+	for _, opts := range []spriteOptions{{back: true, shiny: true}, {version: "ruby"}} {
+		if _, err := spriteUrl(pokemon, opts); err == nil {
+			t.Errorf("%+v: expected an error", opts)
+		}
+	}
+}
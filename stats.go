@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type natureModifier struct {
+	increased string
+	decreased string
+}
+
+// natureModifiers lists the stat each nature raises and lowers by 10%.
+// Hardy, docile, serious, bashful and quirky are neutral and left out.
+var natureModifiers = map[string]natureModifier{
+	"lonely":  {"attack", "defense"},
+	"brave":   {"attack", "speed"},
+	"adamant": {"attack", "special-attack"},
+	"naughty": {"attack", "special-defense"},
+	"bold":    {"defense", "attack"},
+	"relaxed": {"defense", "speed"},
+	"impish":  {"defense", "special-attack"},
+	"lax":     {"defense", "special-defense"},
+	"timid":   {"speed", "attack"},
+	"hasty":   {"speed", "defense"},
+	"jolly":   {"speed", "special-attack"},
+	"naive":   {"speed", "special-defense"},
+	"modest":  {"special-attack", "attack"},
+	"mild":    {"special-attack", "defense"},
+	"quiet":   {"special-attack", "speed"},
+	"rash":    {"special-attack", "special-defense"},
+	"calm":    {"special-defense", "attack"},
+	"gentle":  {"special-defense", "defense"},
+	"sassy":   {"special-defense", "speed"},
+	"careful": {"special-defense", "special-attack"},
+}
+
+const maxIV = 31
+
+const maxEV = 252
+
+const maxTotalEVs = 510
+
+// calcStat applies the standard stat formula used since generation III.
+func calcStat(stat string, base int, iv int, ev int, level int, nature string) int {
+	core := (2*base + iv + ev/4) * level / 100
+
+	if stat == "hp" {
+		// Shedinja is the only pokemon with a base HP of 1 and it always has 1 HP
+		if base == 1 {
+			return 1
+		}
+		return core + level + 10
+	}
+
+	value := core + 5
+	if modifier, ok := natureModifiers[nature]; ok {
+		if modifier.increased == stat {
+			value = value * 110 / 100
+		} else if modifier.decreased == stat {
+			value = value * 90 / 100
+		}
+	}
+
+	return value
+}
+
+// calcStats computes the actual stats of a caught pokemon from its species.
+func calcStats(pokemon Pokemon, caught *CaughtPokemon) map[string]int {
+	stats := map[string]int{}
+	for _, stat := range pokemon.Stats {
+		name := stat.Stat.Name
+		stats[name] = calcStat(name, stat.BaseStat, caught.IVs[name], caught.EVs[name], caught.Level, caught.Nature)
+	}
+	return stats
+}
+
+func natureSummary(nature string) string {
+	modifier, ok := natureModifiers[nature]
+	if !ok {
+		return nature + " (neutral)"
+	}
+	return fmt.Sprintf("%s (+%s, -%s)", nature, modifier.increased, modifier.decreased)
+}
+
+// evYield describes the effort values a pokemon gives when defeated.
+func evYield(pokemon Pokemon) string {
+	yield := []string{}
+	for _, stat := range pokemon.Stats {
+		if stat.Effort > 0 {
+			yield = append(yield, fmt.Sprintf("%d %s", stat.Effort, stat.Stat.Name))
+		}
+	}
+	if len(yield) == 0 {
+		return "none"
+	}
+	return strings.Join(yield, ", ")
+}
+
+// parseStatValues reads either a single number applied to every stat or a
+// comma separated list of stat:value pairs, e.g. "attack:252,speed:252".
+func parseStatValues(text string, limit int) (map[string]int, error) {
+	values := map[string]int{}
+
+	if all, err := strconv.Atoi(text); err == nil {
+		if all < 0 || all > limit {
+			return nil, fmt.Errorf("%d is out of range 0-%d", all, limit)
+		}
+		for _, stat := range statNames {
+			values[stat] = all
+		}
+		return values, nil
+	}
+
+	for _, pair := range strings.Split(text, ",") {
+		name, num, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("Expected stat:value, got %s", pair)
+		}
+		if !isStatName(name) {
+			return nil, fmt.Errorf("Unknown stat %s", name)
+		}
+		value, err := strconv.Atoi(num)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for %s: %s", name, num)
+		}
+		if value < 0 || value > limit {
+			return nil, fmt.Errorf("%s value %d is out of range 0-%d", name, value, limit)
+		}
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+func isStatName(name string) bool {
+	for _, stat := range statNames {
+		if stat == name {
+			return true
+		}
+	}
+	return false
+}
+
+func commandStatCalc(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Usage: statcalc <pokemon> [level=50] [nature=hardy] [ivs=31|stat:n,...] [evs=0|stat:n,...]")
+	}
+
+	pokemon, err := conf.api.getPokemon(args[0])
+	if err != nil {
+		return err
+	}
+
+	build := CaughtPokemon{
+		Species: pokemon.Name,
+		Level:   50,
+		Nature:  "hardy",
+		IVs:     map[string]int{},
+		EVs:     map[string]int{},
+	}
+	for _, stat := range statNames {
+		build.IVs[stat] = maxIV
+	}
+
+	for _, arg := range args[1:] {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("Expected key=value, got %s", arg)
+		}
+
+		switch key {
+		case "level":
+			level, err := strconv.Atoi(value)
+			if err != nil || level < 1 || level > 100 {
+				return errors.New("Level must be between 1 and 100")
+			}
+			build.Level = level
+		case "nature":
+			if !isNature(value) {
+				return fmt.Errorf("Unknown nature %s", value)
+			}
+			build.Nature = value
+		case "ivs":
+			ivs, err := parseStatValues(value, maxIV)
+			if err != nil {
+				return err
+			}
+			for stat, iv := range ivs {
+				build.IVs[stat] = iv
+			}
+		case "evs":
+			evs, err := parseStatValues(value, maxEV)
+			if err != nil {
+				return err
+			}
+			total := 0
+			for stat, ev := range evs {
+				build.EVs[stat] = ev
+				total += ev
+			}
+			if total > maxTotalEVs {
+				return fmt.Errorf("EVs add up to %d, the maximum is %d", total, maxTotalEVs)
+			}
+		default:
+			return fmt.Errorf("Unknown option %s", key)
+		}
+	}
+
+	stats := calcStats(pokemon, &build)
+
+	fmt.Fprintf(conf.out, "%s at level %d, %s\n", pokemon.Name, build.Level, natureSummary(build.Nature))
+	for _, stat := range pokemon.Stats {
+		name := stat.Stat.Name
+		fmt.Fprintf(conf.out, "\t-%s: %d (base %d, IV %d, EV %d)\n", name, stats[name], stat.BaseStat, build.IVs[name], build.EVs[name])
+	}
+
+	return nil
+}
+
+func isNature(name string) bool {
+	for _, nature := range natureNames {
+		if nature == name {
+			return true
+		}
+	}
+	return false
+}
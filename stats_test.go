@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCalcStat(t *testing.T) {
+	// Garchomp at level 78 with an adamant nature, the worked example used by
+	// most stat references.
+	cases := []struct {
+		stat     string
+		base     int
+		iv       int
+		ev       int
+		expected int
+	}{
+		{stat: "hp", base: 108, iv: 24, ev: 74, expected: 289},
+		{stat: "attack", base: 130, iv: 12, ev: 190, expected: 278},
+		{stat: "defense", base: 95, iv: 30, ev: 91, expected: 193},
+		{stat: "special-attack", base: 80, iv: 16, ev: 48, expected: 135},
+		{stat: "special-defense", base: 85, iv: 23, ev: 84, expected: 171},
+		{stat: "speed", base: 102, iv: 5, ev: 23, expected: 171},
+	}
+
+	for _, c := range cases {
+		actual := calcStat(c.stat, c.base, c.iv, c.ev, 78, "adamant")
+		if actual != c.expected {
+			t.Errorf("%s: actual %v != expected %v", c.stat, actual, c.expected)
+		}
+	}
+
+	if hp := calcStat("hp", 1, 31, 252, 100, "hardy"); hp != 1 {
+		t.Errorf("base 1 hp: actual %v != expected 1", hp)
+	}
+}
+
+func TestParseStatValues(t *testing.T) {
+	values, err := parseStatValues("attack:252,speed:252,hp:4", maxEV)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["attack"] != 252 || values["speed"] != 252 || values["hp"] != 4 {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	values, err = parseStatValues("31", maxIV)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != len(statNames) {
+		t.Errorf("expected a value for every stat, got %v", values)
+	}
+
+	if _, err := parseStatValues("attack:300", maxEV); err == nil {
+		t.Errorf("expected an out of range error")
+	}
+	if _, err := parseStatValues("luck:3", maxEV); err == nil {
+		t.Errorf("expected an unknown stat error")
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Storage records where each caught pokemon lives, by pokedex ID: the party
+// the trainer carries around or one of the PC boxes.
+type Storage struct {
+	Party []int   `json:"party"`
+	Boxes [][]int `json:"boxes"`
+}
+
+const maxPartySize = 6
+
+const boxSize = 30
+
+func NewStorage() *Storage {
+	return &Storage{
+		Party: []int{},
+		Boxes: [][]int{{}},
+	}
+}
+
+// Store puts a new pokemon in the party when there is room, otherwise in the
+// first box with a free slot. It reports whether it went to the party.
+func (s *Storage) Store(id int) bool {
+	if len(s.Party) < maxPartySize {
+		s.Party = append(s.Party, id)
+		return true
+	}
+
+	s.addToBox(id)
+	return false
+}
+
+func (s *Storage) addToBox(id int) int {
+	for i := range s.Boxes {
+		if len(s.Boxes[i]) < boxSize {
+			s.Boxes[i] = append(s.Boxes[i], id)
+			return i
+		}
+	}
+
+	s.Boxes = append(s.Boxes, []int{id})
+	return len(s.Boxes) - 1
+}
+
+func (s *Storage) Remove(id int) {
+	s.Party = removeID(s.Party, id)
+	for i := range s.Boxes {
+		s.Boxes[i] = removeID(s.Boxes[i], id)
+	}
+}
+
+// Locate returns the box number of a pokemon, or -1 when it's in the party.
+// ok is false if the pokemon isn't stored at all.
+func (s *Storage) Locate(id int) (box int, slot int, ok bool) {
+	for i, partyID := range s.Party {
+		if partyID == id {
+			return -1, i, true
+		}
+	}
+	for b := range s.Boxes {
+		for i, boxID := range s.Boxes[b] {
+			if boxID == id {
+				return b, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func (s *Storage) InParty(id int) bool {
+	box, _, ok := s.Locate(id)
+	return ok && box == -1
+}
+
+func (s *Storage) slotRef(box int, slot int) *int {
+	if box == -1 {
+		return &s.Party[slot]
+	}
+	return &s.Boxes[box][slot]
+}
+
+// Swap exchanges the positions of two stored pokemon, wherever they are.
+func (s *Storage) Swap(a int, b int) error {
+	boxA, slotA, okA := s.Locate(a)
+	boxB, slotB, okB := s.Locate(b)
+	if !okA || !okB {
+		return errors.New("Both pokemon must be in your party or PC")
+	}
+
+	*s.slotRef(boxA, slotA), *s.slotRef(boxB, slotB) = b, a
+	return nil
+}
+
+// Reconcile makes storage agree with the pokedex: IDs that no longer exist are
+// dropped and any pokemon without a place is stored.
+func (s *Storage) Reconcile(dex *Pokedex) {
+	if len(s.Boxes) == 0 {
+		s.Boxes = [][]int{{}}
+	}
+
+	seen := map[int]bool{}
+	keep := func(ids []int) []int {
+		kept := []int{}
+		for _, id := range ids {
+			if _, ok := dex.Caught[id]; ok && !seen[id] {
+				seen[id] = true
+				kept = append(kept, id)
+			}
+		}
+		return kept
+	}
+
+	s.Party = keep(s.Party)
+	for i := range s.Boxes {
+		s.Boxes[i] = keep(s.Boxes[i])
+	}
+
+	for _, caught := range dex.Sorted() {
+		if !seen[caught.ID] {
+			s.Store(caught.ID)
+		}
+	}
+}
+
+func removeID(ids []int, id int) []int {
+	for i, existing := range ids {
+		if existing == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+func commandParty(conf *config, args []string) error {
+	if len(conf.storage.Party) == 0 {
+		return errors.New("Your party is empty!")
+	}
+
+	fmt.Fprintln(conf.out, "Your party:")
+	for i, id := range conf.storage.Party {
+		fmt.Fprintf(conf.out, "\t%d. %s\n", i+1, conf.pokedex.Caught[id])
+	}
+
+	return nil
+}
+
+func commandBox(conf *config, args []string) error {
+	box := 1
+	if len(args) > 0 {
+		var err error
+		box, err = strconv.Atoi(args[0])
+		if err != nil || box < 1 || box > len(conf.storage.Boxes) {
+			return fmt.Errorf("Box must be between 1 and %d", len(conf.storage.Boxes))
+		}
+	}
+
+	ids := conf.storage.Boxes[box-1]
+	fmt.Fprintf(conf.out, "Box %d/%d (%d/%d):\n", box, len(conf.storage.Boxes), len(ids), boxSize)
+	for _, id := range ids {
+		fmt.Fprintf(conf.out, "\t- %s\n", conf.pokedex.Caught[id])
+	}
+
+	return nil
+}
+
+func commandDeposit(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Must pass a pokemon to the deposit command")
+	}
+
+	caught, err := conf.pokedex.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	if !conf.storage.InParty(caught.ID) {
+		return fmt.Errorf("%s is not in your party", caught.Name())
+	}
+	if len(conf.storage.Party) == 1 {
+		return errors.New("You can't deposit your last pokemon")
+	}
+
+	conf.storage.Party = removeID(conf.storage.Party, caught.ID)
+	box := conf.storage.addToBox(caught.ID)
+	fmt.Fprintf(conf.out, "%s was sent to box %d\n", caught.Name(), box+1)
+
+	return nil
+}
+
+func commandWithdraw(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Must pass a pokemon to the withdraw command")
+	}
+
+	caught, err := conf.pokedex.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	if conf.storage.InParty(caught.ID) {
+		return fmt.Errorf("%s is already in your party", caught.Name())
+	}
+	if len(conf.storage.Party) >= maxPartySize {
+		return errors.New("Your party is full, deposit or swap a pokemon first")
+	}
+
+	conf.storage.Remove(caught.ID)
+	conf.storage.Party = append(conf.storage.Party, caught.ID)
+	fmt.Fprintf(conf.out, "%s joined your party\n", caught.Name())
+
+	return nil
+}
+
+func commandSwap(conf *config, args []string) error {
+	if len(args) < 2 {
+		return errors.New("Usage: swap <pokemon> <pokemon>")
+	}
+
+	a, err := conf.pokedex.Find(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := conf.pokedex.Find(args[1])
+	if err != nil {
+		return err
+	}
+
+	err = conf.storage.Swap(a.ID, b.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(conf.out, "Swapped %s and %s\n", a.Name(), b.Name())
+	return nil
+}
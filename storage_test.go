@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestStorageStore(t *testing.T) {
+	s := NewStorage()
+	for id := 1; id <= maxPartySize+boxSize+1; id++ {
+		inParty := s.Store(id)
+		if inParty != (id <= maxPartySize) {
+			t.Errorf("id %v: inParty %v", id, inParty)
+		}
+	}
+
+	if len(s.Party) != maxPartySize {
+		t.Errorf("party size: %v != %v", len(s.Party), maxPartySize)
+	}
+	if len(s.Boxes) != 2 || len(s.Boxes[0]) != boxSize || len(s.Boxes[1]) != 1 {
+		t.Errorf("unexpected boxes: %v", s.Boxes)
+	}
+}
+
+func TestStorageSwap(t *testing.T) {
+	s := &Storage{
+		Party: []int{1, 2},
+		Boxes: [][]int{{3, 4}},
+	}
+
+	err := s.Swap(1, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Party[0] != 4 || s.Boxes[0][1] != 1 {
+		t.Errorf("swap failed: party %v boxes %v", s.Party, s.Boxes)
+	}
+
+	if err := s.Swap(1, 5); err == nil {
+		t.Errorf("expected an error swapping an unstored pokemon")
+	}
+}
+
+func TestStorageReconcile(t *testing.T) {
+	dex := NewPokedex()
+	for i := 0; i < 3; i++ {
+		dex.Add(&CaughtPokemon{Species: "pikachu"})
+	}
+
+	s := &Storage{
+		Party: []int{1, 9, 1},
+	}
+	s.Reconcile(dex)
+
+	if len(s.Party) != 3 || s.Party[0] != 1 || s.Party[1] != 2 || s.Party[2] != 3 {
+		t.Errorf("unexpected party: %v", s.Party)
+	}
+	if len(s.Boxes) != 1 || len(s.Boxes[0]) != 0 {
+		t.Errorf("unexpected boxes: %v", s.Boxes)
+	}
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// tradeOffer is a proposed swap between two profiles, waiting for the other
+// trainer to accept it. The species of both pokemon are recorded so a trade
+// can't go through after either of them has changed.
+type tradeOffer struct {
+	ID               int       `json:"id"`
+	From             string    `json:"from"`
+	To               string    `json:"to"`
+	Offered          int       `json:"offered"`
+	OfferedSpecies   string    `json:"offered_species"`
+	Requested        int       `json:"requested"`
+	RequestedSpecies string    `json:"requested_species"`
+	Created          time.Time `json:"created"`
+}
+
+// tradeBook holds the open offers of every profile on the machine.
+type tradeBook struct {
+	NextID int          `json:"next_id"`
+	Offers []tradeOffer `json:"offers"`
+}
+
+// tradeSide is one trainer's half of a trade.
+type tradeSide struct {
+	dex   *Pokedex
+	store *Storage
+	id    int
+}
+
+func tradesPath() string {
+	return filepath.Join(filepath.Dir(defaultSavePath()), "trades.json")
+}
+
+func loadTrades() (*tradeBook, error) {
+	book := &tradeBook{NextID: 1, Offers: []tradeOffer{}}
+	data, err := os.ReadFile(tradesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, book)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+	return book, nil
+}
+
+func (b *tradeBook) write() error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(tradesPath()), 0755)
+	if err != nil {
+		return err
+	}
+
+	tmp := tradesPath() + ".tmp"
+	err = os.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, tradesPath())
+}
+
+// find returns an offer the current profile is part of.
+func (b *tradeBook) find(conf *config, param string) (tradeOffer, error) {
+	id, err := strconv.Atoi(param)
+	if err != nil {
+		return tradeOffer{}, fmt.Errorf("Trade offers are picked by number, got %s", param)
+	}
+	for _, offer := range b.Offers {
+		if offer.ID == id && (offer.From == conf.profile || offer.To == conf.profile) {
+			return offer, nil
+		}
+	}
+	return tradeOffer{}, fmt.Errorf("There's no trade offer #%d for you", id)
+}
+
+func (b *tradeBook) remove(id int) {
+	offers := []tradeOffer{}
+	for _, offer := range b.Offers {
+		if offer.ID != id {
+			offers = append(offers, offer)
+		}
+	}
+	b.Offers = offers
+}
+
+// loadProfileSave reads the save of a profile other than the current one.
+func loadProfileSave(conf *config, name string) (trainerSave, error) {
+	if name == conf.profile {
+		return trainerSave{}, errors.New("You can't trade with yourself")
+	}
+	if !profileExists(name) {
+		return trainerSave{}, fmt.Errorf("There's no profile named %s", name)
+	}
+	return readSave(profilePath(name))
+}
+
+// swapPokemon moves each side's pokemon to the other side under a new ID,
+// in the party when there's room. It returns what each side received.
+func swapPokemon(a tradeSide, b tradeSide) (*CaughtPokemon, *CaughtPokemon, error) {
+	fromA, ok := a.dex.Caught[a.id]
+	if !ok {
+		return nil, nil, fmt.Errorf("#%d is no longer available", a.id)
+	}
+	fromB, ok := b.dex.Caught[b.id]
+	if !ok {
+		return nil, nil, fmt.Errorf("#%d is no longer available", b.id)
+	}
+
+	a.dex.Remove(a.id)
+	a.store.Remove(a.id)
+	b.dex.Remove(b.id)
+	b.store.Remove(b.id)
+
+	toA, toB := *fromB, *fromA
+	a.store.Store(a.dex.Add(&toA))
+	a.dex.MarkCaught(toA.dexSpecies(), time.Now())
+	b.store.Store(b.dex.Add(&toB))
+	b.dex.MarkCaught(toB.dexSpecies(), time.Now())
+
+	return &toA, &toB, nil
+}
+
+// tradeEvolution finds what a traded pokemon evolves into, if anything.
+func tradeEvolution(conf *config, caught *CaughtPokemon, tradedFor string) (string, error) {
+	req := evolutionRequest{
+		traded:    true,
+		tradedFor: tradedFor,
+		when:      time.Now(),
+	}
+	ready, _, err := evolutionOptions(conf, caught, req)
+	if errors.Is(err, errDoesntEvolve) {
+		return "", nil
+	}
+	if err != nil || len(ready) == 0 {
+		return "", err
+	}
+	return ready[0], nil
+}
+
+func describeOffer(conf *config, offer tradeOffer) string {
+	if offer.From == conf.profile {
+		return fmt.Sprintf("#%d to %s: your #%d %s for their #%d %s", offer.ID, offer.To, offer.Offered, offer.OfferedSpecies, offer.Requested, offer.RequestedSpecies)
+	}
+	return fmt.Sprintf("#%d from %s: their #%d %s for your #%d %s", offer.ID, offer.From, offer.Offered, offer.OfferedSpecies, offer.Requested, offer.RequestedSpecies)
+}
+
+func describeIndividual(caught *CaughtPokemon) string {
+	return fmt.Sprintf("%s, %s nature, %s, ability %s, IVs %v", caught, caught.Nature, caught.Gender, valueOrNone(caught.Ability), caught.IVs)
+}
+
+func valueOrNone(value string) string {
+	if len(value) == 0 {
+		return "none"
+	}
+	return value
+}
+
+func commandTrade(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Usage: trade propose <profile> <your pokemon> <their pokemon> | trade list | trade review|accept|decline <offer>")
+	}
+
+	book, err := loadTrades()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "propose":
+		if len(args) != 4 {
+			return errors.New("Usage: trade propose <profile> <your pokemon> <their pokemon>")
+		}
+		theirs, err := loadProfileSave(conf, args[1])
+		if err != nil {
+			return err
+		}
+		mine, err := conf.pokedex.Find(args[2])
+		if err != nil {
+			return err
+		}
+		their, err := theirs.Pokedex.Find(args[3])
+		if err != nil {
+			return fmt.Errorf("%s: %v", args[1], err)
+		}
+
+		offer := tradeOffer{
+			ID:               book.NextID,
+			From:             conf.profile,
+			To:               args[1],
+			Offered:          mine.ID,
+			OfferedSpecies:   mine.Species,
+			Requested:        their.ID,
+			RequestedSpecies: their.Species,
+			Created:          time.Now(),
+		}
+		book.NextID++
+		book.Offers = append(book.Offers, offer)
+		err = book.write()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(conf.out, "Offered %s, switch to %s to accept it\n", describeOffer(conf, offer), args[1])
+	case "list":
+		found := false
+		for _, offer := range book.Offers {
+			if offer.From == conf.profile || offer.To == conf.profile {
+				fmt.Fprintf(conf.out, "\t- %s\n", describeOffer(conf, offer))
+				found = true
+			}
+		}
+		if !found {
+			fmt.Fprintln(conf.out, "No open trade offers")
+		}
+	case "review":
+		if len(args) != 2 {
+			return errors.New("Usage: trade review <offer>")
+		}
+		offer, err := book.find(conf, args[1])
+		if err != nil {
+			return err
+		}
+		from, err := readSave(profilePath(offer.From))
+		if err != nil {
+			return err
+		}
+		to, err := readSave(profilePath(offer.To))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(conf.out, describeOffer(conf, offer))
+		for _, side := range []struct {
+			name string
+			dex  *Pokedex
+			id   int
+		}{{offer.From, from.Pokedex, offer.Offered}, {offer.To, to.Pokedex, offer.Requested}} {
+			if caught, ok := side.dex.Caught[side.id]; ok {
+				fmt.Fprintf(conf.out, "%s sends %s\n", side.name, describeIndividual(caught))
+			} else {
+				fmt.Fprintf(conf.out, "%s no longer has #%d\n", side.name, side.id)
+			}
+		}
+	case "accept":
+		if len(args) != 2 {
+			return errors.New("Usage: trade accept <offer>")
+		}
+		offer, err := book.find(conf, args[1])
+		if err != nil {
+			return err
+		}
+		if offer.To != conf.profile {
+			return fmt.Errorf("Only %s can accept offer #%d", offer.To, offer.ID)
+		}
+		return acceptTrade(conf, book, offer)
+	case "decline":
+		if len(args) != 2 {
+			return errors.New("Usage: trade decline <offer>")
+		}
+		offer, err := book.find(conf, args[1])
+		if err != nil {
+			return err
+		}
+		book.remove(offer.ID)
+		err = book.write()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(conf.out, "Trade offer #%d was called off\n", offer.ID)
+	default:
+		return fmt.Errorf("Unknown trade command %s, use propose, list, review, accept or decline", args[0])
+	}
+
+	return nil
+}
+
+// acceptTrade swaps the pokemon and writes both saves together, so either
+// both trainers end up with their new pokemon or nothing changes. The swap is
+// made on a copy of the current trainer, which only replaces the session's
+// pokedex once both saves are written. The other profile is locked for the
+// trade, so it can't be traded with while another pokedex is using it.
+func acceptTrade(conf *config, book *tradeBook, offer tradeOffer) error {
+	err := lockProfile(offer.From)
+	if err != nil {
+		return err
+	}
+	defer unlockProfile(offer.From)
+
+	theirs, err := loadProfileSave(conf, offer.From)
+	if err != nil {
+		return err
+	}
+	mine, err := copySave(currentSave(conf))
+	if err != nil {
+		return err
+	}
+
+	offered, ok := theirs.Pokedex.Caught[offer.Offered]
+	requested, ok2 := mine.Pokedex.Caught[offer.Requested]
+	if !ok || !ok2 || offered.Species != offer.OfferedSpecies || requested.Species != offer.RequestedSpecies {
+		book.remove(offer.ID)
+		err = book.write()
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("The pokemon in offer #%d have changed since it was made, so it was called off", offer.ID)
+	}
+
+	// Work out the evolutions first, they need the API and may fail
+	mineEvolvesInto, err := tradeEvolution(conf, offered, requested.Species)
+	if err != nil {
+		return err
+	}
+	theirsEvolvesInto, err := tradeEvolution(conf, requested, offered.Species)
+	if err != nil {
+		return err
+	}
+
+	received, sent, err := swapPokemon(
+		tradeSide{mine.Pokedex, mine.Storage, offer.Requested},
+		tradeSide{theirs.Pokedex, theirs.Storage, offer.Offered},
+	)
+	if err != nil {
+		return err
+	}
+
+	messages := []string{fmt.Sprintf("You sent %s to %s and received %s", requested.Name(), offer.From, received)}
+	if len(mineEvolvesInto) > 0 {
+		previous, err := conf.api.evolvePokemon(mine.Pokedex, received, mineEvolvesInto)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, fmt.Sprintf("Congratulations! Your %s evolved into %s!", previous, received.Species))
+	}
+	if len(theirsEvolvesInto) > 0 {
+		previous, err := conf.api.evolvePokemon(theirs.Pokedex, sent, theirsEvolvesInto)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, fmt.Sprintf("%s's %s evolved into %s!", offer.From, previous, sent.Species))
+	}
+
+	theirs.Version = saveVersion
+	err = writeSaves(map[string]trainerSave{
+		conf.saveFile:           mine,
+		profilePath(offer.From): theirs,
+	})
+	if err != nil {
+		return err
+	}
+	conf.pokedex = mine.Pokedex
+	conf.storage = mine.Storage
+
+	book.remove(offer.ID)
+	err = book.write()
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		fmt.Fprintln(conf.out, message)
+	}
+	return nil
+}
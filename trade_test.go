@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSwapPokemon(t *testing.T) {
+	caughtAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mine, myStore := NewPokedex(), NewStorage()
+	myStore.Store(mine.Add(&CaughtPokemon{Species: "kadabra", Level: 20, CaughtAt: caughtAt}))
+	myStore.Store(mine.Add(&CaughtPokemon{Species: "pidgey", Level: 4, CaughtAt: caughtAt}))
+
+	theirs, theirStore := NewPokedex(), NewStorage()
+	theirStore.Store(theirs.Add(&CaughtPokemon{Species: "machoke", Level: 28, CaughtAt: caughtAt}))
+
+	received, sent, err := swapPokemon(tradeSide{mine, myStore, 1}, tradeSide{theirs, theirStore, 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Species != "machoke" || received.ID != 3 || mine.Caught[3] != received {
+		t.Errorf("expected to receive machoke as #3, got %v", received)
+	}
+	if sent.Species != "kadabra" || sent.ID != 2 || theirs.Caught[2] != sent {
+		t.Errorf("expected them to receive kadabra as #2, got %v", sent)
+	}
+	if _, ok := mine.Caught[1]; ok {
+		t.Errorf("kadabra should have left")
+	}
+	if len(myStore.Party) != 2 || myStore.Party[0] != 2 || myStore.Party[1] != 3 {
+		t.Errorf("unexpected party %v", myStore.Party)
+	}
+	if len(theirStore.Party) != 1 || theirStore.Party[0] != 2 {
+		t.Errorf("unexpected party for them %v", theirStore.Party)
+	}
+	if mine.Status("machoke") != "caught" || theirs.Status("kadabra") != "caught" {
+		t.Errorf("traded species should be marked as caught")
+	}
+
+	if _, _, err := swapPokemon(tradeSide{mine, myStore, 1}, tradeSide{theirs, theirStore, 2}); err == nil {
+		t.Errorf("expected an error trading a pokemon that's gone")
+	}
+}
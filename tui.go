@@ -0,0 +1,358 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// The panes of the full screen mode, in the order tab moves through them.
+const (
+	locationPane = iota
+	encounterPane
+	detailPane
+	paneCount
+)
+
+// tuiState is everything the full screen mode shows. Drawing it doesn't need
+// the terminal or the API, so it can be tested on its own.
+type tuiState struct {
+	page       int
+	pages      int
+	locations  []string
+	area       string
+	encounters []string
+	details    []string
+	focus      int
+	cursor     [paneCount]int
+	status     string
+}
+
+// parseKeys turns what was read from the terminal into key names. Keys
+// typed quickly arrive together, and arrow keys arrive as escape sequences.
+func parseKeys(input []byte) []string {
+	keys := []string{}
+	for len(input) > 0 {
+		size := 1
+		if len(input) >= 3 && input[0] == '\x1b' && input[1] == '[' {
+			size = 3
+		}
+		keys = append(keys, keyName(string(input[:size])))
+		input = input[size:]
+	}
+	return keys
+}
+
+func keyName(key string) string {
+	switch key {
+	case "\x1b[A", "k":
+		return "up"
+	case "\x1b[B", "j":
+		return "down"
+	case "\x1b[C", "l", "\t":
+		return "right"
+	case "\x1b[D", "h", "\x1b[Z":
+		return "left"
+	case "\r", "\n":
+		return "enter"
+	case "\x03", "q":
+		return "quit"
+	}
+	return key
+}
+
+func (s *tuiState) paneLength(pane int) int {
+	switch pane {
+	case locationPane:
+		return len(s.locations)
+	case encounterPane:
+		return len(s.encounters)
+	}
+	return len(s.details)
+}
+
+// move changes the selection of the focused pane, keeping it in bounds.
+func (s *tuiState) move(delta int) {
+	last := s.paneLength(s.focus) - 1
+	s.cursor[s.focus] = max(0, min(s.cursor[s.focus]+delta, last))
+}
+
+func (s *tuiState) selected(pane int) string {
+	list := s.locations
+	if pane == encounterPane {
+		list = s.encounters
+	}
+	if s.cursor[pane] < len(list) {
+		return list[s.cursor[pane]]
+	}
+	return ""
+}
+
+// fit cuts or pads text to exactly width characters.
+func fit(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
+
+// paneLines renders one pane's list to height lines. The selected line of
+// a list pane is drawn in reverse video, and the list scrolls to keep it in
+// view. The detail pane scrolls from its cursor instead.
+func (s *tuiState) paneLines(pane int, title string, list []string, width int, height int) []string {
+	lines := []string{fit(title, width)}
+	cursor := s.cursor[pane]
+
+	offset := max(0, cursor-(height-2))
+	if pane == detailPane {
+		offset = cursor
+	}
+
+	for i := offset; len(lines) < height; i++ {
+		if i >= len(list) {
+			lines = append(lines, fit("", width))
+			continue
+		}
+		line := fit(list[i], width)
+		if pane != detailPane && i == cursor {
+			if s.focus == pane {
+				line = "\x1b[7m" + line + "\x1b[0m"
+			} else {
+				line = "\x1b[1m" + line + "\x1b[0m"
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// render draws the whole screen as width by height lines. Tiny terminals
+// are drawn as if they were just big enough.
+func (s *tuiState) render(width int, height int) []string {
+	width = max(width, 24)
+	height = max(height, 4)
+	paneWidth := (width - 6) / 3
+	bodyHeight := height - 2
+
+	header := fmt.Sprintf(" Pokedex  page %d/%d  arrows/hjkl move, tab switch, enter open, n/p page, c catch, i inspect, q quit", s.page, s.pages)
+	areaTitle := "Encounters"
+	if len(s.area) > 0 {
+		areaTitle += ": " + s.area
+	}
+
+	columns := [][]string{
+		s.paneLines(locationPane, "Locations", s.locations, paneWidth, bodyHeight),
+		s.paneLines(encounterPane, areaTitle, s.encounters, paneWidth, bodyHeight),
+		s.paneLines(detailPane, "Details", s.details, width-6-2*paneWidth, bodyHeight),
+	}
+
+	lines := []string{"\x1b[7m" + fit(header, width) + "\x1b[0m"}
+	for row := 0; row < bodyHeight; row++ {
+		lines = append(lines, columns[0][row]+" │ "+columns[1][row]+" │ "+columns[2][row])
+	}
+	lines = append(lines, fit(s.status, width))
+	return lines
+}
+
+// captureOutput runs an action with the command output going to the
+// returned lines instead of the terminal the screen is drawn on.
+func captureOutput(conf *config, action func() error) ([]string, error) {
+	var buffer bytes.Buffer
+	out := conf.out
+	conf.out = &buffer
+	err := action()
+	conf.out = out
+
+	lines := []string{}
+	for _, line := range strings.Split(strings.TrimRight(buffer.String(), "\n"), "\n") {
+		if len(line) > 0 {
+			lines = append(lines, strings.ReplaceAll(line, "\t", "  "))
+		}
+	}
+	return lines, err
+}
+
+func (s *tuiState) loadPage(conf *config, page int) error {
+	lines, pages, err := conf.api.fetchMapPage("", page, conf.mapLimit)
+	if err != nil {
+		return err
+	}
+	s.locations = lines
+	s.page = page
+	s.pages = pages
+	s.cursor[locationPane] = 0
+	conf.mapRegion = ""
+	conf.mapPage = page
+	return nil
+}
+
+func (s *tuiState) loadArea(conf *config, name string) error {
+	lines, err := captureOutput(conf, func() error {
+		return commandExplore(conf, []string{name})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.area = name
+	s.encounters = []string{}
+	for _, line := range lines {
+		if pokemon, ok := strings.CutPrefix(line, "- "); ok {
+			s.encounters = append(s.encounters, pokemon)
+		}
+	}
+	s.cursor[encounterPane] = 0
+	s.focus = encounterPane
+	return nil
+}
+
+// showDetails fills the detail pane with the output of a command.
+func (s *tuiState) showDetails(conf *config, command func(*config, []string) error, args ...string) error {
+	lines, err := captureOutput(conf, func() error {
+		return command(conf, args)
+	})
+	s.details = lines
+	s.cursor[detailPane] = 0
+	return err
+}
+
+// handleKey applies a key press, returning false when it's time to quit.
+func (s *tuiState) handleKey(conf *config, key string) bool {
+	var err error
+	s.status = ""
+
+	switch key {
+	case "quit":
+		return false
+	case "up":
+		s.move(-1)
+	case "down":
+		s.move(1)
+	case "left":
+		s.focus = (s.focus + paneCount - 1) % paneCount
+	case "right":
+		s.focus = (s.focus + 1) % paneCount
+	case "n":
+		if s.page >= s.pages {
+			err = fmt.Errorf("you're on the last page")
+		} else {
+			err = s.loadPage(conf, s.page+1)
+		}
+	case "p":
+		if s.page <= 1 {
+			err = fmt.Errorf("you're on the first page")
+		} else {
+			err = s.loadPage(conf, s.page-1)
+		}
+	case "enter":
+		switch s.focus {
+		case locationPane:
+			err = s.loadArea(conf, s.selected(locationPane))
+		case encounterPane:
+			err = s.showDetails(conf, commandInfo, s.selected(encounterPane))
+		}
+	case "c":
+		if conf.battle != nil {
+			err = fmt.Errorf("You're in a battle! Leave the full screen mode to finish it")
+			break
+		}
+		if len(s.selected(encounterPane)) == 0 {
+			err = fmt.Errorf("Pick a pokemon to catch first")
+			break
+		}
+		err = s.showDetails(conf, commandCatch, s.selected(encounterPane))
+		if err == nil {
+			err = writeSave(conf.saveFile, conf)
+		}
+	case "i":
+		if len(s.selected(encounterPane)) == 0 {
+			err = fmt.Errorf("Pick a pokemon to inspect first")
+			break
+		}
+		err = s.showDetails(conf, commandInspect, s.selected(encounterPane))
+	}
+
+	if err != nil {
+		s.status = err.Error()
+	}
+	return true
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	return strings.TrimSpace(string(output)), err
+}
+
+func terminalSize() (int, int) {
+	size, err := stty("size")
+	if err != nil {
+		return 80, 24
+	}
+	rows, cols, _ := strings.Cut(size, " ")
+	height, err := strconv.Atoi(rows)
+	if err != nil {
+		return 80, 24
+	}
+	width, err := strconv.Atoi(cols)
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// runTui takes over the terminal until q is pressed. Raw mode comes from
+// stty so no terminal library is needed.
+func runTui(conf *config) error {
+	saved, err := stty("-g")
+	if err != nil {
+		return fmt.Errorf("The full screen mode needs a terminal: %v", err)
+	}
+	_, err = stty("raw", "-echo")
+	if err != nil {
+		return err
+	}
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		stty(saved)
+	}()
+
+	// The list shows location areas, so a page of a region's map doesn't
+	// carry over
+	state := &tuiState{}
+	page := 1
+	if len(conf.mapRegion) == 0 && conf.mapPage > 0 {
+		page = conf.mapPage
+	}
+	err = state.loadPage(conf, page)
+	if err != nil && page > 1 {
+		err = state.loadPage(conf, 1)
+	}
+	if err != nil {
+		state.status = err.Error()
+	}
+
+	input := bufio.NewReader(os.Stdin)
+	buf := make([]byte, 16)
+	for {
+		width, height := terminalSize()
+		fmt.Print("\x1b[H" + strings.Join(state.render(width, height), "\r\n") + "\x1b[J")
+
+		n, err := input.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			if !state.handleKey(conf, key) {
+				return writeSave(conf.saveFile, conf)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{"\x1b[A", []string{"up"}},
+		{"j", []string{"down"}},
+		{"\t", []string{"right"}},
+		{"\x1b[D", []string{"left"}},
+		{"\r", []string{"enter"}},
+		{"\x03", []string{"quit"}},
+		{"c", []string{"c"}},
+		{"j\x1b[Bq", []string{"down", "down", "quit"}},
+	}
+
+	for _, c := range cases {
+		actual := parseKeys([]byte(c.input))
+		if strings.Join(actual, ",") != strings.Join(c.expected, ",") {
+			t.Errorf("%q: actual %v != expected %v", c.input, actual, c.expected)
+		}
+	}
+}
+
+func TestTuiMove(t *testing.T) {
+	state := &tuiState{locations: []string{"a", "b", "c"}}
+
+	state.move(-1)
+	if state.cursor[locationPane] != 0 {
+		t.Errorf("actual %d != expected 0", state.cursor[locationPane])
+	}
+	state.move(5)
+	if state.cursor[locationPane] != 2 {
+		t.Errorf("actual %d != expected 2", state.cursor[locationPane])
+	}
+	if state.selected(locationPane) != "c" {
+		t.Errorf("actual %s != expected c", state.selected(locationPane))
+	}
+	if state.selected(encounterPane) != "" {
+		t.Errorf("expected no encounter to be selected")
+	}
+}
+
+func TestTuiRender(t *testing.T) {
+	state := &tuiState{page: 2, pages: 5, status: "hello"}
+	for i := 0; i < 30; i++ {
+		state.locations = append(state.locations, strings.Repeat("x", 40))
+	}
+	state.cursor[locationPane] = 25
+
+	lines := state.render(80, 24)
+	if len(lines) != 24 {
+		t.Fatalf("actual %d lines != expected 24", len(lines))
+	}
+	if !strings.Contains(lines[0], "page 2/5") {
+		t.Errorf("header %q is missing the page", lines[0])
+	}
+	if strings.TrimSpace(lines[23]) != "hello" {
+		t.Errorf("actual status %q != expected hello", lines[23])
+	}
+
+	// The selected location is scrolled into view on the last body line
+	if !strings.HasPrefix(lines[22], "\x1b[7m") {
+		t.Errorf("expected the selection on the last line, got %q", lines[22])
+	}
+	for _, line := range lines[1:23] {
+		plain := strings.NewReplacer("\x1b[7m", "", "\x1b[0m", "").Replace(line)
+		if len([]rune(plain)) != 80 {
+			t.Errorf("line %q is %d wide", plain, len([]rune(plain)))
+		}
+	}
+}
+
+func TestTuiLoadArea(t *testing.T) {
+	conf, _ := newTestConfig(t, map[string]string{
+		"https://pokeapi.co/api/v2/location-area/viridian-forest-area/": viridianForestJson,
+		"https://pokeapi.co/api/v2/pokemon/caterpie/":                   caterpieJson,
+		"https://pokeapi.co/api/v2/pokemon/pikachu/":                    pikachuJson,
+	})
+	state := &tuiState{locations: []string{"viridian-forest-area"}}
+	state.handleKey(conf, "enter")
+
+	if len(state.status) > 0 {
+		t.Fatalf("unexpected error: %s", state.status)
+	}
+	if state.focus != encounterPane || len(state.encounters) != 2 || state.encounters[1] != "pikachu" {
+		t.Errorf("unexpected encounters %v", state.encounters)
+	}
+	if conf.currentArea != "viridian-forest-area" {
+		t.Errorf("actual area %s != expected viridian-forest-area", conf.currentArea)
+	}
+
+	state.handleKey(conf, "i")
+	if len(state.status) == 0 {
+		t.Errorf("expected inspecting an uncaught pokemon to fail")
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mikeheiberger/pokedexcli/internal/battle"
+)
+
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// resourceID pulls the numeric ID out of an API resource URL such as
+// https://pokeapi.co/api/v2/version-group/25/, or 0 if there isn't one.
+func resourceID(url string) int {
+	id, err := strconv.Atoi(path.Base(strings.TrimSuffix(url, "/")))
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// resourceList is the shape of every paged list endpoint in the API.
+type resourceList struct {
+	Count   int             `json:"count"`
+	Next    string          `json:"next"`
+	Prev    string          `json:"previous"`
+	Results []namedResource `json:"results"`
+}
+
+type TypeResponse struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageFrom []namedResource `json:"double_damage_from"`
+		DoubleDamageTo   []namedResource `json:"double_damage_to"`
+		HalfDamageFrom   []namedResource `json:"half_damage_from"`
+		HalfDamageTo     []namedResource `json:"half_damage_to"`
+		NoDamageFrom     []namedResource `json:"no_damage_from"`
+		NoDamageTo       []namedResource `json:"no_damage_to"`
+	} `json:"damage_relations"`
+}
+
+// multipliers in the order matchups are listed.
+var multipliers = []float64{4, 2, 0.5, 0.25, 0}
+
+func (c *apiClient) getType(name string) (TypeResponse, error) {
+	const baseUrl = "https://pokeapi.co/api/v2/type/"
+
+	var poketype TypeResponse
+	jsonData, err := c.getJsonFromCacheOrServer(baseUrl + name + "/")
+	if err != nil {
+		return poketype, err
+	}
+
+	err = json.Unmarshal(jsonData, &poketype)
+	if err != nil {
+		return poketype, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+
+	return poketype, nil
+}
+
+// loadTypeChart fills a type chart with the damage relations of each of the
+// given attacking types.
+func (c *apiClient) loadTypeChart(attackTypes []string) (battle.TypeChart, error) {
+	chart := battle.TypeChart{}
+	for _, name := range attackTypes {
+		if _, ok := chart[name]; ok || len(name) == 0 {
+			continue
+		}
+
+		poketype, err := c.getType(name)
+		if err != nil {
+			return nil, err
+		}
+
+		chart[name] = map[string]float64{}
+		for _, defend := range poketype.DamageRelations.DoubleDamageTo {
+			chart.Set(name, defend.Name, 2)
+		}
+		for _, defend := range poketype.DamageRelations.HalfDamageTo {
+			chart.Set(name, defend.Name, 0.5)
+		}
+		for _, defend := range poketype.DamageRelations.NoDamageTo {
+			chart.Set(name, defend.Name, 0)
+		}
+	}
+
+	return chart, nil
+}
+
+// getAllTypes lists the names of every type pokemon can have. The API also
+// has "unknown" and "shadow" types which have no matchups and are skipped.
+func (c *apiClient) getAllTypes() ([]string, error) {
+	jsonData, err := c.getJsonFromCacheOrServer("https://pokeapi.co/api/v2/type/?limit=100")
+	if err != nil {
+		return nil, err
+	}
+
+	var list resourceList
+	err = json.Unmarshal(jsonData, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshal failed: %v", err)
+	}
+
+	names := []string{}
+	for _, result := range list.Results {
+		if result.Name == "unknown" || result.Name == "shadow" {
+			continue
+		}
+		names = append(names, result.Name)
+	}
+	return names, nil
+}
+
+// loadFullTypeChart loads every matchup once, the first time they're needed.
+func (c *apiClient) loadFullTypeChart() (battle.TypeChart, error) {
+	if c.typeChart != nil {
+		return c.typeChart, nil
+	}
+
+	names, err := c.getAllTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	chart, err := c.loadTypeChart(names)
+	if err != nil {
+		return nil, err
+	}
+
+	c.typeChart = chart
+	return chart, nil
+}
+
+// defensiveMatchups groups every attacking type by how much damage it does to
+// a pokemon with the given types. Neutral matchups are left out.
+func defensiveMatchups(chart battle.TypeChart, types []string) map[float64][]string {
+	matchups := map[float64][]string{}
+	for attack := range chart {
+		multiplier := chart.Effectiveness(attack, types...)
+		if multiplier != 1 {
+			matchups[multiplier] = append(matchups[multiplier], attack)
+		}
+	}
+	for _, names := range matchups {
+		sort.Strings(names)
+	}
+	return matchups
+}
+
+func printMatchups(out io.Writer, matchups map[float64][]string) {
+	for _, multiplier := range multipliers {
+		if names, ok := matchups[multiplier]; ok {
+			fmt.Fprintf(out, "\t- %gx: %s\n", multiplier, strings.Join(names, ", "))
+		}
+	}
+}
+
+func resourceNames(resources []namedResource) string {
+	if len(resources) == 0 {
+		return "none"
+	}
+	names := []string{}
+	for _, resource := range resources {
+		names = append(names, resource.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func commandType(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Must pass a type to the type command")
+	}
+
+	poketype, err := conf.api.getType(args[0])
+	if err != nil {
+		return err
+	}
+
+	relations := poketype.DamageRelations
+	fmt.Fprintf(conf.out, "Type: %s\n", poketype.Name)
+	fmt.Fprintln(conf.out, "Attacking:")
+	fmt.Fprintf(conf.out, "\t- Super effective against: %s\n", resourceNames(relations.DoubleDamageTo))
+	fmt.Fprintf(conf.out, "\t- Not very effective against: %s\n", resourceNames(relations.HalfDamageTo))
+	fmt.Fprintf(conf.out, "\t- No effect on: %s\n", resourceNames(relations.NoDamageTo))
+	fmt.Fprintln(conf.out, "Defending:")
+	fmt.Fprintf(conf.out, "\t- Weak to: %s\n", resourceNames(relations.DoubleDamageFrom))
+	fmt.Fprintf(conf.out, "\t- Resists: %s\n", resourceNames(relations.HalfDamageFrom))
+	fmt.Fprintf(conf.out, "\t- Immune to: %s\n", resourceNames(relations.NoDamageFrom))
+
+	return nil
+}
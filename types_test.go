@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mikeheiberger/pokedexcli/internal/battle"
+)
+
+func TestDefensiveMatchups(t *testing.T) {
+	chart := battle.TypeChart{
+		"normal": {"ghost": 0},
+		"ground": {"electric": 2, "flying": 0, "fire": 2},
+		"rock":   {"fire": 2, "flying": 2, "ground": 0.5},
+		"grass":  {"fire": 0.5, "flying": 0.5, "ground": 2},
+		"ice":    {"fire": 0.5, "flying": 2, "ground": 2},
+		"water":  {"fire": 2, "ground": 2},
+		"fire":   {"fire": 0.5},
+	}
+
+	cases := []struct {
+		types    []string
+		expected map[float64]string
+	}{
+		{
+			// Fire doubles ground's damage but flying is immune to it
+			types:    []string{"fire", "flying"},
+			expected: map[float64]string{4: "rock", 2: "water", 0.5: "fire", 0.25: "grass", 0: "ground"},
+		},
+		{
+			types:    []string{"electric", "flying"},
+			expected: map[float64]string{2: "ice,rock", 0.5: "grass", 0: "ground"},
+		},
+		{
+			types:    []string{"ground"},
+			expected: map[float64]string{2: "grass,ice,water", 0.5: "rock"},
+		},
+		{
+			types:    []string{"ghost", "fire"},
+			expected: map[float64]string{2: "ground,rock,water", 0.5: "fire,grass,ice", 0: "normal"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := defensiveMatchups(chart, c.types)
+		if len(actual) != len(c.expected) {
+			t.Errorf("%v: actual %v != expected %v", c.types, actual, c.expected)
+			continue
+		}
+		for multiplier, names := range c.expected {
+			if strings.Join(actual[multiplier], ",") != names {
+				t.Errorf("%v %gx: actual %v != expected %s", c.types, multiplier, actual[multiplier], names)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+type LocationAreaEncounter struct {
+	LocationArea   namedResource `json:"location_area"`
+	VersionDetails []struct {
+		MaxChance        int           `json:"max_chance"`
+		Version          namedResource `json:"version"`
+		EncounterDetails []struct {
+			Chance   int           `json:"chance"`
+			MinLevel int           `json:"min_level"`
+			MaxLevel int           `json:"max_level"`
+			Method   namedResource `json:"method"`
+		} `json:"encounter_details"`
+	} `json:"version_details"`
+}
+
+// encounterRow is one way to find a pokemon: an area, in one version, by
+// one method.
+type encounterRow struct {
+	area     string
+	version  string
+	method   string
+	minLevel int
+	maxLevel int
+	chance   int
+}
+
+// encounterRows merges the encounter slots that share an area, version and
+// method. Each slot has its own chance, so they add up.
+func encounterRows(encounters []LocationAreaEncounter) []encounterRow {
+	rows := []encounterRow{}
+	index := map[string]int{}
+	for _, encounter := range encounters {
+		for _, version := range encounter.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				key := encounter.LocationArea.Name + "/" + version.Version.Name + "/" + detail.Method.Name
+				i, ok := index[key]
+				if !ok {
+					index[key] = len(rows)
+					rows = append(rows, encounterRow{
+						area:     encounter.LocationArea.Name,
+						version:  version.Version.Name,
+						method:   detail.Method.Name,
+						minLevel: detail.MinLevel,
+						maxLevel: detail.MaxLevel,
+						chance:   detail.Chance,
+					})
+					continue
+				}
+				rows[i].minLevel = min(rows[i].minLevel, detail.MinLevel)
+				rows[i].maxLevel = max(rows[i].maxLevel, detail.MaxLevel)
+				rows[i].chance = min(100, rows[i].chance+detail.Chance)
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].chance != rows[j].chance {
+			return rows[i].chance > rows[j].chance
+		}
+		return rows[i].area < rows[j].area
+	})
+	return rows
+}
+
+func (r encounterRow) levels() string {
+	if r.minLevel == r.maxLevel {
+		return fmt.Sprintf("Lv.%d", r.minLevel)
+	}
+	return fmt.Sprintf("Lv.%d-%d", r.minLevel, r.maxLevel)
+}
+
+func commandWhere(conf *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Must pass a pokemon to the where command")
+	}
+
+	pokemon, err := conf.api.getPokemonForSpecies(args[0])
+	if err != nil {
+		return err
+	}
+
+	var encounters []LocationAreaEncounter
+	err = conf.api.getJsonResource(pokemon.LocationAreaEncounters, &encounters)
+	if err != nil {
+		return err
+	}
+
+	rows := encounterRows(encounters)
+	if len(rows) == 0 {
+		fmt.Fprintf(conf.out, "%s can't be found in the wild\n", pokemon.Name)
+		return nil
+	}
+
+	table := [][]string{{"area", "version", "method", "levels", "chance"}}
+	for _, row := range rows {
+		table = append(table, []string{row.area, row.version, row.method, row.levels(), fmt.Sprintf("%d%%", row.chance)})
+	}
+	fmt.Fprintf(conf.out, "%s can be found in:\n", pokemon.Name)
+	printTable(conf.out, table)
+
+	return nil
+}
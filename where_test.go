@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEncounterRows(t *testing.T) {
+	data := `[
+		{
+			"location_area": {"name": "viridian-forest-area"},
+			"version_details": [
+				{
+					"max_chance": 10,
+					"version": {"name": "red"},
+					"encounter_details": [
+						{"chance": 5, "min_level": 3, "max_level": 3, "method": {"name": "walk"}},
+						{"chance": 5, "min_level": 5, "max_level": 5, "method": {"name": "walk"}}
+					]
+				}
+			]
+		},
+		{
+			"location_area": {"name": "power-plant-area"},
+			"version_details": [
+				{
+					"max_chance": 25,
+					"version": {"name": "red"},
+					"encounter_details": [
+						{"chance": 25, "min_level": 21, "max_level": 24, "method": {"name": "walk"}}
+					]
+				}
+			]
+		}
+	]`
+
+	var encounters []LocationAreaEncounter
+	err := json.Unmarshal([]byte(data), &encounters)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []encounterRow{
+		{area: "power-plant-area", version: "red", method: "walk", minLevel: 21, maxLevel: 24, chance: 25},
+		{area: "viridian-forest-area", version: "red", method: "walk", minLevel: 3, maxLevel: 5, chance: 10},
+	}
+	actual := encounterRows(encounters)
+	if len(actual) != len(expected) {
+		t.Fatalf("actual %v != expected %v", actual, expected)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("row %d: actual %v != expected %v", i, actual[i], expected[i])
+		}
+	}
+	if actual[1].levels() != "Lv.3-5" {
+		t.Errorf("levels: actual %s", actual[1].levels())
+	}
+}